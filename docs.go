@@ -0,0 +1,51 @@
+package gofsen
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"strings"
+)
+
+//go:embed assets/docs.html
+var docsPage string
+
+// docsTemplate page de documentation autonome : aucune ressource externe (CDN),
+// elle fonctionne donc hors ligne
+var docsTemplate = template.Must(template.New("docs").Parse(docsPage))
+
+// DocsConfig configuration de la page de documentation de l'API
+type DocsConfig struct {
+	Title string // titre de la page, celui du document OpenAPI par défaut
+
+	// SpecURL est l'URL d'un document OpenAPI JSON fourni par l'application.
+	// Si vide, le document généré à partir des routes est servi sous
+	// <path>/openapi.json avec la configuration OpenAPI.
+	SpecURL string
+	OpenAPI OpenAPIConfig
+}
+
+// ServeDocs sert sur path une page HTML interactive qui liste les opérations
+// du document OpenAPI et permet d'envoyer des requêtes de test
+func (r *Router) ServeDocs(path string, config DocsConfig) {
+	if config.SpecURL == "" {
+		config.SpecURL = strings.TrimSuffix(path, "/") + "/openapi.json"
+		r.ServeOpenAPI(config.SpecURL, config.OpenAPI)
+	}
+	if config.Title == "" {
+		config.Title = config.OpenAPI.Title
+	}
+	if config.Title == "" {
+		config.Title = "API Documentation"
+	}
+
+	var page bytes.Buffer
+	if err := docsTemplate.Execute(&page, config); err != nil {
+		panic("gofsen: docs page: " + err.Error())
+	}
+
+	route := r.GET(path, func(c *Context) {
+		c.HTML(page.String())
+	})
+	route.hidden = true
+}
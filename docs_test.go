@@ -0,0 +1,72 @@
+package gofsen
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeDocs(t *testing.T) {
+	app := New()
+	app.GET("/users/:id<int>", func(c *Context) {})
+	app.ServeDocs("/docs", DocsConfig{OpenAPI: OpenAPIConfig{Title: "Users <API>", Version: "1.0.0"}})
+
+	req := httptest.NewRequest("GET", "/docs", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/html" {
+		t.Errorf("Expected Content-Type 'text/html', got '%s'", ct)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "<title>Users &lt;API&gt;</title>") {
+		t.Errorf("Expected escaped title in page, got %s", body)
+	}
+	if !strings.Contains(body, `var specURL = "/docs/openapi.json";`) {
+		t.Errorf("Expected page to point at generated spec, got %s", body)
+	}
+	for _, external := range []string{"<script src", "<link", "https://", "http://"} {
+		if strings.Contains(body, external) {
+			t.Errorf("Expected self-contained page, found %q", external)
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/docs/openapi.json", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status 200 for spec, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"/users/{id}"`) {
+		t.Errorf("Expected generated paths in spec, got %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), `"/docs"`) {
+		t.Error("Expected docs routes to be hidden from spec")
+	}
+}
+
+func TestServeDocsCustomSpec(t *testing.T) {
+	app := New()
+	app.ServeDocs("/reference", DocsConfig{Title: "Billing", SpecURL: "/static/billing.json"})
+
+	req := httptest.NewRequest("GET", "/reference", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), `var specURL = "/static/billing.json";`) {
+		t.Errorf("Expected page to point at custom spec, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/reference/openapi.json", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 404 {
+		t.Errorf("Expected no generated spec with custom SpecURL, got status %d", w.Code)
+	}
+}
@@ -0,0 +1,130 @@
+package gofsen
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HandlerFuncE handler qui retourne une erreur au lieu d'écrire lui-même la
+// réponse d'erreur ; à enregistrer via WrapE
+type HandlerFuncE func(*Context) error
+
+// MiddlewareFuncE middleware qui retourne une erreur ; à enregistrer via WrapMiddlewareE
+type MiddlewareFuncE func(*Context) error
+
+// ErrorHandlerFunc rend la réponse d'une erreur retournée par un handler
+type ErrorHandlerFunc func(*Context, error)
+
+// HTTPError erreur portant le statut HTTP et le message destinés au client.
+// Code est un identifiant applicatif stable (ex: "user_not_found") et Cause
+// l'erreur d'origine, journalisée mais jamais envoyée au client.
+type HTTPError struct {
+	Status  int
+	Code    string
+	Message string
+	Cause   error
+}
+
+// NewHTTPError crée une HTTPError ; un message vide prend le texte du statut
+func NewHTTPError(status int, code, message string) *HTTPError {
+	return &HTTPError{Status: status, Code: code, Message: message}
+}
+
+// WithCause retourne une copie de l'erreur avec sa cause
+func (e *HTTPError) WithCause(cause error) *HTTPError {
+	clone := *e
+	clone.Cause = cause
+	return &clone
+}
+
+func (e *HTTPError) Error() string {
+	message := fmt.Sprintf("%d %s", e.Status, e.message())
+	if e.Cause != nil {
+		message += ": " + e.Cause.Error()
+	}
+	return message
+}
+
+// Unwrap permet errors.Is / errors.As sur la cause
+func (e *HTTPError) Unwrap() error {
+	return e.Cause
+}
+
+// message retourne le message destiné au client
+func (e *HTTPError) message() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return http.StatusText(e.Status)
+}
+
+// WrapE convertit un handler qui retourne une erreur en HandlerFunc.
+// L'erreur est transmise au gestionnaire d'erreurs du router.
+func WrapE(handler HandlerFuncE) HandlerFunc {
+	return func(c *Context) {
+		if err := handler(c); err != nil {
+			c.handleError(err)
+		}
+	}
+}
+
+// WrapMiddlewareE convertit un middleware qui retourne une erreur en MiddlewareFunc.
+// L'erreur est transmise au gestionnaire d'erreurs du router.
+func WrapMiddlewareE(middleware MiddlewareFuncE) MiddlewareFunc {
+	return func(c *Context) {
+		if err := middleware(c); err != nil {
+			c.handleError(err)
+		}
+	}
+}
+
+// ErrorHandler définit le rendu des erreurs retournées par les handlers et
+// middlewares (WrapE, WrapMiddlewareE) et des panics capturés par Recovery.
+// Les middlewares en amont retrouvent l'erreur via Context.Err après c.Next().
+func (r *Router) ErrorHandler(handler ErrorHandlerFunc) {
+	r.errorHandler = handler
+}
+
+// Err retourne la dernière erreur transmise au gestionnaire d'erreurs pour cette requête
+func (c *Context) Err() error {
+	return c.err
+}
+
+// handleError enregistre l'erreur sur le contexte, interrompt la chaîne et
+// fait rendre l'erreur par le router
+func (c *Context) handleError(err error) {
+	c.err = err
+	c.Abort()
+	if c.router != nil && c.router.errorHandler != nil {
+		c.router.errorHandler(c, err)
+		return
+	}
+	defaultErrorHandler(c, err)
+}
+
+// defaultErrorHandler rend un Problem tel quel, une HTTPError avec son statut
+// et toute autre erreur en 500 sans en révéler le détail. Rien n'est écrit si
+// la réponse est partie.
+func defaultErrorHandler(c *Context, err error) {
+	if c.Written() {
+		return
+	}
+
+	var problem *Problem
+	if errors.As(err, &problem) {
+		c.Problem(*problem)
+		return
+	}
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		httpErr = &HTTPError{Status: http.StatusInternalServerError}
+	}
+
+	var extensions map[string]interface{}
+	if httpErr.Code != "" {
+		extensions = map[string]interface{}{"code": httpErr.Code}
+	}
+	c.renderError(httpErr.Status, httpErr.message(), extensions)
+}
@@ -0,0 +1,192 @@
+package gofsen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+var errNotFound = errors.New("record not found")
+
+func TestHandlerErrors(t *testing.T) {
+	app := New()
+	app.GET("/users/:id", WrapE(func(c *Context) error {
+		if c.Param("id") != "1" {
+			return NewHTTPError(404, "user_not_found", "User not found").WithCause(errNotFound)
+		}
+		c.Text("user 1")
+		return nil
+	}))
+	app.GET("/crash", WrapE(func(c *Context) error {
+		return errors.New("database password is hunter2")
+	}))
+
+	tests := []struct {
+		path    string
+		code    int
+		message string
+		errCode string
+	}{
+		{"/users/2", 404, "User not found", "user_not_found"},
+		{"/crash", 500, "Internal Server Error", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.code, w.Code)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: invalid JSON response: %v", tt.path, err)
+		}
+		if body["error"] != tt.message {
+			t.Errorf("%s: expected error '%s', got '%v'", tt.path, tt.message, body["error"])
+		}
+		if code, _ := body["code"].(string); code != tt.errCode {
+			t.Errorf("%s: expected code '%s', got '%s'", tt.path, tt.errCode, code)
+		}
+		if strings.Contains(w.Body.String(), "hunter2") || strings.Contains(w.Body.String(), errNotFound.Error()) {
+			t.Errorf("%s: expected cause to stay private, got %s", tt.path, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Body.String() != "user 1" {
+		t.Errorf("Expected 200 'user 1', got %d '%s'", w.Code, w.Body.String())
+	}
+}
+
+func TestErrorHandlerHook(t *testing.T) {
+	var observed error
+	app := New()
+	app.Use(func(c *Context) {
+		c.Next()
+		observed = c.Err()
+	})
+	app.Use(Recovery())
+	app.ErrorHandler(func(c *Context, err error) {
+		status := 500
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			status = httpErr.Status
+		}
+		c.Status(status).Text("custom: " + err.Error())
+	})
+	app.GET("/users/:id", WrapE(func(c *Context) error {
+		return NewHTTPError(404, "user_not_found", "User not found").WithCause(errNotFound)
+	}))
+	app.GET("/crash", WrapE(func(c *Context) error {
+		return errors.New("database password is hunter2")
+	}))
+	app.GET("/panic", func(c *Context) { panic("boom") })
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/users/2", 404, "custom: 404 User not found: record not found"},
+		{"/crash", 500, "custom: database password is hunter2"},
+		{"/panic", 500, "custom: 500 Internal Server Error: panic: boom"},
+	}
+
+	for _, tt := range tests {
+		observed = nil
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Errorf("%s: expected %d '%s', got %d '%s'", tt.path, tt.code, tt.body, w.Code, w.Body.String())
+		}
+		if observed == nil {
+			t.Errorf("%s: expected middleware to observe the error", tt.path)
+		}
+	}
+
+	observed = nil
+	req := httptest.NewRequest("GET", "/users/2", nil)
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	if !errors.Is(observed, errNotFound) {
+		t.Errorf("Expected observed error to wrap its cause, got %v", observed)
+	}
+}
+
+func TestMiddlewareErrors(t *testing.T) {
+	app := New()
+	app.Use(WrapMiddlewareE(func(c *Context) error {
+		if c.Request.Header.Get("Authorization") == "" {
+			return NewHTTPError(401, "unauthorized", "")
+		}
+		c.Next()
+		return nil
+	}))
+	app.GET("/private", func(c *Context) { c.Text("secret") })
+
+	req := httptest.NewRequest("GET", "/private", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 401 {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"error":"Unauthorized"`) {
+		t.Errorf("Expected status text as message, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/private", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Body.String() != "secret" {
+		t.Errorf("Expected 200 'secret', got %d '%s'", w.Code, w.Body.String())
+	}
+}
+
+func TestErrorAfterWrite(t *testing.T) {
+	app := New()
+	app.GET("/partial", WrapE(func(c *Context) error {
+		c.Text("partial")
+		return errors.New("stream interrupted")
+	}))
+
+	req := httptest.NewRequest("GET", "/partial", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Body.String() != "partial" {
+		t.Errorf("Expected response to be left untouched, got %d '%s'", w.Code, w.Body.String())
+	}
+}
+
+func TestLoggerReportsErrors(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	app.Use(Logger())
+	app.GET("/users/:id", WrapE(func(c *Context) error {
+		return NewHTTPError(404, "user_not_found", "User not found").WithCause(errNotFound)
+	}))
+
+	req := httptest.NewRequest("GET", "/users/2", nil)
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(buf.String(), "GET /users/2 404 ") || !strings.Contains(buf.String(), "error: 404 User not found: record not found") {
+		t.Errorf("Expected error in log, got '%s'", buf.String())
+	}
+}
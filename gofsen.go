@@ -2,13 +2,18 @@
 package gofsen
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -20,9 +25,27 @@ const Version = "v1.0.0"
 type Route struct {
 	Method  string
 	Path    string
+	Host    string
+	Version string
+	Name    string
 	Handler HandlerFunc
-	Pattern *regexp.Regexp
 	Params  []string
+	Summary string
+
+	// Deprecated: le routage passe par un arbre radix, Pattern vaut toujours nil.
+	// Conservé pour la compatibilité du code qui lit Routes().
+	Pattern *regexp.Regexp
+
+	Tags       []string
+	Deprecated bool
+	Metadata   map[string]interface{}
+	group      *RouteGroup
+	router     *Router
+
+	// Documentation OpenAPI (voir Accepts et Returns)
+	requestBody reflect.Type
+	responses   map[int]reflect.Type
+	hidden      bool
 }
 
 // Context encapsule les informations de la requête et réponse
@@ -31,6 +54,12 @@ type Context struct {
 	ResponseWriter  http.ResponseWriter
 	Params          map[string]string
 	Query           map[string]string
+	router          *Router
+	writer          *responseWriter
+	version         string
+	err             error
+	aborted         bool
+	store           *contextStore
 	middleware      []MiddlewareFunc
 	middlewareIndex int
 }
@@ -41,11 +70,27 @@ type HandlerFunc func(*Context)
 // MiddlewareFunc définit le type de fonction pour les middlewares
 type MiddlewareFunc func(*Context)
 
+// anyMethods liste les méthodes enregistrées par Any
+var anyMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "CONNECT", "TRACE"}
+
 // Router structure principale du framework
 type Router struct {
-	routes      []Route
+	routes      []*Route
+	trees       map[string]*node
+	hosts       []*hostRouter
+	versions    map[string]*versionRouter
+	versioning  VersionConfig
 	middlewares []MiddlewareFunc
 	groups      map[string]*RouteGroup
+	names       map[string]*Route
+	strict      bool
+	pathMode    PathMode
+	fallbacks   []staticFallback
+
+	notFound         HandlerFunc
+	methodNotAllowed HandlerFunc
+	errorHandler     ErrorHandlerFunc
+	problemDetails   bool
 }
 
 // RouteGroup pour organiser les routes
@@ -53,13 +98,19 @@ type RouteGroup struct {
 	prefix      string
 	middlewares []MiddlewareFunc
 	router      *Router
+	parent      *RouteGroup
+	host        *hostRouter
+	version     *versionRouter
 }
 
 // New crée une nouvelle instance du router Gofsen
 func New() *Router {
 	return &Router{
-		routes: make([]Route, 0),
-		groups: make(map[string]*RouteGroup),
+		routes:   make([]*Route, 0),
+		trees:    make(map[string]*node),
+		versions: make(map[string]*versionRouter),
+		groups:   make(map[string]*RouteGroup),
+		names:    make(map[string]*Route),
 	}
 }
 
@@ -79,39 +130,98 @@ func (r *Router) Group(prefix string) *RouteGroup {
 }
 
 // addRoute ajoute une route au router
-func (r *Router) addRoute(method, path string, handler HandlerFunc) {
-	// Convertir les paramètres dynamiques en regex
-	pattern, params := convertPathToRegex(path)
+func (r *Router) addRoute(method, path string, handler HandlerFunc) *Route {
+	return r.addScopedRoute(nil, nil, method, path, handler)
+}
+
+// addScopedRoute ajoute une route, restreinte à un motif d'hôte si host est
+// non nil ou à une version si version est non nil
+func (r *Router) addScopedRoute(host *hostRouter, version *versionRouter, method, path string, handler HandlerFunc) *Route {
+	validatePath(path)
 
-	route := Route{
+	route := &Route{
 		Method:  method,
 		Path:    path,
 		Handler: handler,
-		Pattern: pattern,
-		Params:  params,
+		Params:  parseParams(path),
+		router:  r,
+	}
+
+	// Insérer la route dans l'arbre radix de sa méthode (et de son hôte ou de sa version)
+	trees := r.trees
+	if host != nil {
+		route.Host = host.pattern
+		trees = host.trees
 	}
+	if version != nil {
+		route.Version = version.name
+		route.Deprecated = version.deprecation != nil
+		trees = version.trees
+	}
+	root := trees[method]
+	if root == nil {
+		root = &node{kind: staticKind}
+		trees[method] = root
+	}
+	if r.strict {
+		if existing := root.overlapping(path); existing != nil {
+			panic(newConflictError(route, existing, true))
+		}
+	}
+	if err := root.insert(path, route); err != nil {
+		panic(err)
+	}
+
 	r.routes = append(r.routes, route)
+	return route
+}
+
+// SetStrict active le mode strict : en plus des doublons, toute route qui
+// chevauche une route existante (ex: /users/me et /users/:id) provoque un panic
+// à l'enregistrement au lieu d'être départagée par la priorité statique > :param > *
+func (r *Router) SetStrict(strict bool) {
+	r.strict = strict
 }
 
 // Méthodes HTTP
-func (r *Router) GET(path string, handler HandlerFunc) {
-	r.addRoute("GET", path, handler)
+func (r *Router) GET(path string, handler HandlerFunc) *Route {
+	return r.addRoute("GET", path, handler)
+}
+
+func (r *Router) POST(path string, handler HandlerFunc) *Route {
+	return r.addRoute("POST", path, handler)
+}
+
+func (r *Router) PUT(path string, handler HandlerFunc) *Route {
+	return r.addRoute("PUT", path, handler)
+}
+
+func (r *Router) DELETE(path string, handler HandlerFunc) *Route {
+	return r.addRoute("DELETE", path, handler)
 }
 
-func (r *Router) POST(path string, handler HandlerFunc) {
-	r.addRoute("POST", path, handler)
+func (r *Router) PATCH(path string, handler HandlerFunc) *Route {
+	return r.addRoute("PATCH", path, handler)
 }
 
-func (r *Router) PUT(path string, handler HandlerFunc) {
-	r.addRoute("PUT", path, handler)
+func (r *Router) HEAD(path string, handler HandlerFunc) *Route {
+	return r.addRoute("HEAD", path, handler)
 }
 
-func (r *Router) DELETE(path string, handler HandlerFunc) {
-	r.addRoute("DELETE", path, handler)
+func (r *Router) OPTIONS(path string, handler HandlerFunc) *Route {
+	return r.addRoute("OPTIONS", path, handler)
 }
 
-func (r *Router) PATCH(path string, handler HandlerFunc) {
-	r.addRoute("PATCH", path, handler)
+// Handle enregistre une route pour une méthode HTTP quelconque
+func (r *Router) Handle(method, path string, handler HandlerFunc) *Route {
+	return r.addRoute(method, path, handler)
+}
+
+// Any enregistre le même handler pour toutes les méthodes HTTP standard
+func (r *Router) Any(path string, handler HandlerFunc) {
+	for _, method := range anyMethods {
+		r.addRoute(method, path, handler)
+	}
 }
 
 // RouteGroup methods
@@ -119,103 +229,339 @@ func (g *RouteGroup) Use(middleware MiddlewareFunc) {
 	g.middlewares = append(g.middlewares, middleware)
 }
 
-func (g *RouteGroup) GET(path string, handler HandlerFunc) {
-	g.router.addRoute("GET", g.prefix+path, handler)
+// Group crée un sous-groupe qui hérite du préfixe et des middlewares du groupe
+func (g *RouteGroup) Group(prefix string) *RouteGroup {
+	group := &RouteGroup{
+		prefix:  g.prefix + prefix,
+		router:  g.router,
+		parent:  g,
+		host:    g.host,
+		version: g.version,
+	}
+	g.router.groups[group.prefix] = group
+	return group
+}
+
+// chain retourne les middlewares du groupe précédés de ceux de ses parents
+func (g *RouteGroup) chain() []MiddlewareFunc {
+	if g.parent == nil {
+		return g.middlewares
+	}
+	parent := g.parent.chain()
+	chain := make([]MiddlewareFunc, 0, len(parent)+len(g.middlewares))
+	chain = append(chain, parent...)
+	return append(chain, g.middlewares...)
+}
+
+// addRoute enregistre une route préfixée rattachée au groupe
+func (g *RouteGroup) addRoute(method, path string, handler HandlerFunc) *Route {
+	route := g.router.addScopedRoute(g.host, g.version, method, g.prefix+path, handler)
+	route.group = g
+	return route
+}
+
+func (g *RouteGroup) GET(path string, handler HandlerFunc) *Route {
+	return g.addRoute("GET", path, handler)
+}
+
+func (g *RouteGroup) POST(path string, handler HandlerFunc) *Route {
+	return g.addRoute("POST", path, handler)
+}
+
+func (g *RouteGroup) PUT(path string, handler HandlerFunc) *Route {
+	return g.addRoute("PUT", path, handler)
+}
+
+func (g *RouteGroup) DELETE(path string, handler HandlerFunc) *Route {
+	return g.addRoute("DELETE", path, handler)
 }
 
-func (g *RouteGroup) POST(path string, handler HandlerFunc) {
-	g.router.addRoute("POST", g.prefix+path, handler)
+func (g *RouteGroup) PATCH(path string, handler HandlerFunc) *Route {
+	return g.addRoute("PATCH", path, handler)
 }
 
-func (g *RouteGroup) PUT(path string, handler HandlerFunc) {
-	g.router.addRoute("PUT", g.prefix+path, handler)
+func (g *RouteGroup) HEAD(path string, handler HandlerFunc) *Route {
+	return g.addRoute("HEAD", path, handler)
 }
 
-func (g *RouteGroup) DELETE(path string, handler HandlerFunc) {
-	g.router.addRoute("DELETE", g.prefix+path, handler)
+func (g *RouteGroup) OPTIONS(path string, handler HandlerFunc) *Route {
+	return g.addRoute("OPTIONS", path, handler)
 }
 
-func (g *RouteGroup) PATCH(path string, handler HandlerFunc) {
-	g.router.addRoute("PATCH", g.prefix+path, handler)
+func (g *RouteGroup) Handle(method, path string, handler HandlerFunc) *Route {
+	return g.addRoute(method, path, handler)
+}
+
+func (g *RouteGroup) Any(path string, handler HandlerFunc) {
+	for _, method := range anyMethods {
+		g.addRoute(method, path, handler)
+	}
 }
 
 // ServeHTTP implémente l'interface http.Handler
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	writer := &responseWriter{ResponseWriter: w}
 	ctx := &Context{
 		Request:         req,
-		ResponseWriter:  w,
+		ResponseWriter:  writer,
+		writer:          writer,
 		Params:          make(map[string]string),
 		Query:           parseQuery(req.URL.RawQuery),
+		router:          r,
 		middleware:      r.middlewares,
 		middlewareIndex: -1,
 	}
+	ctx.attachStore()
+
+	// Version demandée (en-tête, media type ou version par défaut)
+	var version *versionRouter
+	if len(r.versions) > 0 {
+		ctx.version = r.requestVersion(req)
+		if version = r.findVersion(ctx.version); version != nil {
+			ctx.version = version.name
+		}
+	}
 
 	// Trouver la route correspondante
-	route, params := r.findRoute(req.Method, req.URL.Path)
+	path, raw := routingPath(req.URL)
+	route, params, head := r.lookup(req.Host, version, req.Method, path, raw)
+	if route == nil && r.pathMode != PathStrict {
+		// Chemin non canonique (slash final, //, ., ..) : rediriger ou servir la forme canonique
+		if fixed := r.fixPath(req.Host, version, req.Method, path); fixed != "" {
+			if r.pathMode == PathRedirect {
+				// Redirection servie après les middlewares globaux (Logger, CORS...)
+				route = &Route{Method: req.Method, Path: path, Handler: redirectHandler(fixed, raw)}
+			} else {
+				route, params, head = r.lookup(req.Host, version, req.Method, fixed, raw)
+			}
+		}
+	}
+	if route != nil && route.Version != "" {
+		// La réponse dépend de la version demandée
+		w.Header().Add("Vary", r.versionHeader())
+		w.Header().Add("Vary", "Accept")
+		if version.deprecation != nil {
+			version.deprecation.setHeaders(w.Header())
+		}
+	}
+	if head {
+		// HEAD servi par le handler GET, sans corps de réponse
+		ctx.ResponseWriter = headResponseWriter{writer}
+	}
 	if route == nil {
-		ctx.Status(404).JSON(map[string]string{"error": "Route not found"})
-		return
+		allowed := r.allowedMethods(req.Host, version, path)
+		if len(allowed) == 0 && r.pathMode != PathStrict {
+			allowed = r.fixAllowedMethods(req.Host, version, path)
+		}
+		if len(allowed) > 0 && req.Method == "OPTIONS" {
+			// Réponse OPTIONS automatique, après les middlewares globaux (CORS...)
+			route = &Route{Method: "OPTIONS", Path: path, Handler: optionsHandler(allowed)}
+		} else if len(allowed) > 0 {
+			// Le chemin existe pour d'autres méthodes : 405 plutôt que 404
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			route = &Route{Method: req.Method, Path: path, Handler: r.methodNotAllowedHandler()}
+		} else if route, params = r.fallbackRoute(req.Method, path, raw); route == nil {
+			route = &Route{Method: req.Method, Path: path, Handler: r.notFoundHandler()}
+		}
 	}
 
 	// Ajouter les paramètres de route au contexte
-	ctx.Params = params
-
-	// Exécuter les middlewares puis le handler
-	finalHandler := func(c *Context) {
-		route.Handler(c)
+	if params != nil {
+		ctx.Params = params
 	}
-	ctx.middleware = append(ctx.middleware, finalHandler)
+
+	// Exécuter les middlewares globaux, ceux du groupe, puis le handler
+	// (nouvelle slice pour ne jamais écrire dans le tableau partagé r.middlewares)
+	routeMiddlewares := route.Middlewares()
+	chain := make([]MiddlewareFunc, 0, len(r.middlewares)+len(routeMiddlewares)+1)
+	chain = append(chain, r.middlewares...)
+	chain = append(chain, routeMiddlewares...)
+	chain = append(chain, MiddlewareFunc(route.Handler))
+	ctx.middleware = chain
 	ctx.Next()
+
+	// Envoyer le statut d'une réponse sans corps (ex: c.Status(204))
+	writer.writeHeaderNow()
+}
+
+// NotFound définit le handler des requêtes sans route correspondante.
+// Il est exécuté après les middlewares globaux (Logger, CORS...).
+func (r *Router) NotFound(handler HandlerFunc) {
+	r.notFound = handler
+}
+
+// MethodNotAllowed définit le handler des requêtes dont le chemin n'existe que
+// pour d'autres méthodes. L'en-tête Allow est déjà positionné à son exécution.
+func (r *Router) MethodNotAllowed(handler HandlerFunc) {
+	r.methodNotAllowed = handler
+}
+
+func (r *Router) notFoundHandler() HandlerFunc {
+	if r.notFound != nil {
+		return r.notFound
+	}
+	return func(c *Context) {
+		if r.problemDetails {
+			c.Problem(Problem{Status: 404, Detail: "Route not found"})
+			return
+		}
+		c.Status(404).JSON(map[string]string{"error": "Route not found"})
+	}
+}
+
+func (r *Router) methodNotAllowedHandler() HandlerFunc {
+	if r.methodNotAllowed != nil {
+		return r.methodNotAllowed
+	}
+	return func(c *Context) {
+		if r.problemDetails {
+			c.Problem(Problem{Status: 405, Detail: "Method not allowed"})
+			return
+		}
+		c.Status(405).JSON(map[string]string{"error": "Method not allowed"})
+	}
+}
+
+// lookup trouve la route pour la requête, HEAD retombant sur GET (head vaut alors true).
+// Les routes de la version demandée passent avant les autres.
+// Si raw est vrai, le chemin est encore encodé et les paramètres sont décodés.
+func (r *Router) lookup(host string, version *versionRouter, method, path string, raw bool) (route *Route, params map[string]string, head bool) {
+	route, params = r.findVersionRoute(host, version, method, path)
+	if route == nil && method == "HEAD" {
+		route, params = r.findVersionRoute(host, version, "GET", path)
+		head = route != nil
+	}
+	if raw {
+		for key, value := range params {
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				params[key] = unescaped
+			}
+		}
+	}
+	return route, params, head
 }
 
 // findRoute trouve la route correspondante à la méthode et au chemin
 func (r *Router) findRoute(method, path string) (*Route, map[string]string) {
-	for _, route := range r.routes {
-		if route.Method == method {
-			if route.Pattern != nil {
-				// Route avec paramètres dynamiques
-				if matches := route.Pattern.FindStringSubmatch(path); matches != nil {
-					params := make(map[string]string)
-					for i, param := range route.Params {
-						if i+1 < len(matches) {
-							params[param] = matches[i+1]
-						}
-					}
-					return &route, params
-				}
-			} else if route.Path == path {
-				// Route exacte
-				return &route, make(map[string]string)
+	route, values := matchTrees(r.trees, method, path)
+	return route, paramsMap(route, values, nil, nil)
+}
+
+// findVersionRoute cherche parmi les routes de la version, puis parmi les autres routes
+func (r *Router) findVersionRoute(host string, version *versionRouter, method, path string) (*Route, map[string]string) {
+	if version != nil {
+		if route, values := matchTrees(version.trees, method, path); route != nil {
+			return route, paramsMap(route, values, nil, nil)
+		}
+	}
+	return r.findHostRoute(host, method, path)
+}
+
+// findHostRoute cherche d'abord parmi les routes des hôtes correspondant à host,
+// puis parmi les routes sans restriction d'hôte
+func (r *Router) findHostRoute(host, method, path string) (*Route, map[string]string) {
+	if len(r.hosts) > 0 {
+		host = stripPort(host)
+		for _, h := range r.hosts {
+			hostValues, ok := h.match(host)
+			if !ok {
+				continue
+			}
+			if route, values := matchTrees(h.trees, method, path); route != nil {
+				return route, paramsMap(route, values, h.params, hostValues)
 			}
 		}
 	}
-	return nil, nil
+	return r.findRoute(method, path)
 }
 
-// convertPathToRegex convertit un chemin avec paramètres en regex
-func convertPathToRegex(path string) (*regexp.Regexp, []string) {
-	if !strings.Contains(path, ":") {
+// matchTrees recherche le chemin dans l'arbre de la méthode
+func matchTrees(trees map[string]*node, method, path string) (*Route, []string) {
+	root := trees[method]
+	if root == nil {
 		return nil, nil
 	}
+	return root.match(path, nil)
+}
 
-	var params []string
-	regexPath := path
+// paramsMap associe les valeurs capturées aux noms des paramètres de la route
+// et de l'hôte ; retourne nil s'il n'y a aucun paramètre
+func paramsMap(route *Route, values []string, hostNames, hostValues []string) map[string]string {
+	if route == nil || len(values)+len(hostValues) == 0 {
+		return nil
+	}
 
-	// Remplacer :param par ([^/]+)
-	paramRegex := regexp.MustCompile(`:([^/]+)`)
-	matches := paramRegex.FindAllStringSubmatch(path, -1)
+	params := make(map[string]string, len(values)+len(hostValues))
+	for i, value := range hostValues {
+		params[hostNames[i]] = value
+	}
+	for i, value := range values {
+		if i < len(route.Params) {
+			params[route.Params[i]] = value
+		}
+	}
+	return params
+}
 
-	for _, match := range matches {
-		params = append(params, match[1])
-		regexPath = strings.Replace(regexPath, match[0], "([^/]+)", 1)
+// allowedMethods retourne, triées, les méthodes ayant une route pour ce chemin,
+// y compris HEAD (déduit de GET) et OPTIONS (toujours géré automatiquement)
+func (r *Router) allowedMethods(host string, version *versionRouter, path string) []string {
+	var allowed []string
+	has := func(method string) bool {
+		for _, m := range allowed {
+			if m == method {
+				return true
+			}
+		}
+		return false
+	}
+	collect := func(trees map[string]*node) {
+		for method, root := range trees {
+			if route, _ := root.match(path, nil); route != nil && !has(method) {
+				allowed = append(allowed, method)
+			}
+		}
 	}
 
-	pattern, err := regexp.Compile("^" + regexPath + "$")
-	if err != nil {
-		return nil, nil
+	if version != nil {
+		collect(version.trees)
+	}
+	host = stripPort(host)
+	for _, h := range r.hosts {
+		if _, ok := h.match(host); ok {
+			collect(h.trees)
+		}
+	}
+	collect(r.trees)
+	if len(allowed) == 0 {
+		return nil
 	}
 
-	return pattern, params
+	if has("GET") && !has("HEAD") {
+		allowed = append(allowed, "HEAD")
+	}
+	if !has("OPTIONS") {
+		allowed = append(allowed, "OPTIONS")
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// optionsHandler répond à une requête OPTIONS avec les méthodes autorisées
+func optionsHandler(allowed []string) HandlerFunc {
+	return func(c *Context) {
+		c.ResponseWriter.Header().Set("Allow", strings.Join(allowed, ", "))
+		c.Status(204)
+	}
+}
+
+// headResponseWriter ignore le corps de la réponse pour les requêtes HEAD
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
 }
 
 // Listen démarre le serveur sur le port spécifié
@@ -228,17 +574,55 @@ func (r *Router) Listen(port string) error {
 	return http.ListenAndServe(port, r)
 }
 
+// Middlewares retourne les middlewares propres à la route (ceux de son groupe
+// et de ses groupes parents), évalués à chaque requête pour inclure les appels
+// à Use postérieurs à l'enregistrement
+func (route *Route) Middlewares() []MiddlewareFunc {
+	if route.group == nil {
+		return nil
+	}
+	return route.group.chain()
+}
+
 // Context methods
 
-// Next exécute le middleware suivant dans la chaîne
+// Next exécute le middleware suivant dans la chaîne, sauf si elle a été interrompue
 func (c *Context) Next() {
+	if c.aborted {
+		return
+	}
 	c.middlewareIndex++
 	if c.middlewareIndex < len(c.middleware) {
 		c.middleware[c.middlewareIndex](c)
 	}
 }
 
-// Status définit le code de statut HTTP
+// Abort interrompt la chaîne : les appels suivants à Next n'exécutent plus
+// rien. Les middlewares en amont reprennent la main normalement au retour de
+// leur c.Next() et peuvent le détecter avec IsAborted.
+func (c *Context) Abort() {
+	c.aborted = true
+}
+
+// AbortWithStatus interrompt la chaîne et définit le statut de la réponse
+func (c *Context) AbortWithStatus(code int) {
+	c.Abort()
+	c.Status(code)
+}
+
+// AbortWithError interrompt la chaîne et transmet err au gestionnaire d'erreurs
+// du router avec le statut code. Le message envoyé est le texte du statut,
+// err restant accessible via Err.
+func (c *Context) AbortWithError(code int, err error) {
+	c.handleError(&HTTPError{Status: code, Cause: err})
+}
+
+// IsAborted indique si la chaîne a été interrompue
+func (c *Context) IsAborted() bool {
+	return c.aborted
+}
+
+// Status définit le code de statut HTTP, envoyé avec la première écriture du corps
 func (c *Context) Status(code int) *Context {
 	c.ResponseWriter.WriteHeader(code)
 	return c
@@ -267,6 +651,71 @@ func (c *Context) Param(key string) string {
 	return c.Params[key]
 }
 
+// ErrMissingParam est retournée par les accesseurs typés quand le paramètre est absent
+var ErrMissingParam = errors.New("gofsen: missing route parameter")
+
+// ParamInt récupère un paramètre de route converti en int
+func (c *Context) ParamInt(key string) (int, error) {
+	value, ok := c.Params[key]
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrMissingParam, key)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("gofsen: route parameter %q: %w", key, err)
+	}
+	return n, nil
+}
+
+// ParamInt64 récupère un paramètre de route converti en int64
+func (c *Context) ParamInt64(key string) (int64, error) {
+	value, ok := c.Params[key]
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrMissingParam, key)
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("gofsen: route parameter %q: %w", key, err)
+	}
+	return n, nil
+}
+
+// ParamUUID récupère un paramètre de route converti en UUID
+func (c *Context) ParamUUID(key string) (UUID, error) {
+	value, ok := c.Params[key]
+	if !ok {
+		return UUID{}, fmt.Errorf("%w %q", ErrMissingParam, key)
+	}
+	id, err := ParseUUID(value)
+	if err != nil {
+		return UUID{}, fmt.Errorf("gofsen: route parameter %q: %w", key, err)
+	}
+	return id, nil
+}
+
+// UUID représente un identifiant RFC 9562 sur 16 octets
+type UUID [16]byte
+
+// ParseUUID parse un UUID au format canonique xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func ParseUUID(s string) (UUID, error) {
+	var id UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return id, fmt.Errorf("invalid UUID %q", s)
+	}
+
+	hexDigits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(id[:], []byte(hexDigits)); err != nil {
+		return UUID{}, fmt.Errorf("invalid UUID %q", s)
+	}
+	return id, nil
+}
+
+// String retourne la forme canonique en minuscules de l'UUID
+func (id UUID) String() string {
+	h := hex.EncodeToString(id[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
 // QueryParam récupère un paramètre de query string
 func (c *Context) QueryParam(key string) string {
 	return c.Query[key]
@@ -279,13 +728,30 @@ func (c *Context) BindJSON(v interface{}) error {
 
 // Error envoie une réponse d'erreur
 func (c *Context) Error(code int, message string) {
-	c.Status(code).JSON(map[string]interface{}{
+	c.renderError(code, message, nil)
+}
+
+// renderError écrit une réponse d'erreur, au format problem+json si le router
+// l'exige (voir SetProblemDetails), sinon au format JSON historique
+func (c *Context) renderError(code int, message string, extensions map[string]interface{}) {
+	if c.router != nil && c.router.problemDetails {
+		c.Problem(Problem{Status: code, Detail: message, Extensions: extensions})
+		return
+	}
+
+	body := map[string]interface{}{
 		"error":  message,
 		"status": code,
 		"path":   c.Request.URL.Path,
 		"method": c.Request.Method,
 		"time":   time.Now().Format(time.RFC3339),
-	})
+	}
+	for key, value := range extensions {
+		if _, ok := body[key]; !ok {
+			body[key] = value
+		}
+	}
+	c.Status(code).JSON(body)
 }
 
 // Middlewares prédéfinis
@@ -297,9 +763,23 @@ func Logger() MiddlewareFunc {
 		c.Next()
 		duration := time.Since(start)
 
-		log.Printf("%s %s - %v",
+		if err := c.Err(); err != nil {
+			log.Printf("%s %s %d %dB - %v - error: %v",
+				c.Request.Method,
+				c.Request.URL.Path,
+				c.StatusCode(),
+				c.Size(),
+				duration,
+				err,
+			)
+			return
+		}
+
+		log.Printf("%s %s %d %dB - %v",
 			c.Request.Method,
 			c.Request.URL.Path,
+			c.StatusCode(),
+			c.Size(),
 			duration,
 		)
 	}
@@ -311,7 +791,7 @@ func Recovery() MiddlewareFunc {
 		defer func() {
 			if r := recover(); r != nil {
 				log.Printf("PANIC: %v", r)
-				c.Error(500, "Internal Server Error")
+				c.handleError(&HTTPError{Status: 500, Cause: fmt.Errorf("panic: %v", r)})
 			}
 		}()
 		c.Next()
@@ -471,15 +951,24 @@ func parseQuery(rawQuery string) map[string]string {
 
 // Routes retourne toutes les routes enregistrées
 func (r *Router) Routes() []Route {
-	// Trier les routes par méthode puis par chemin
+	// Trier les routes par méthode, chemin, hôte puis version
 	routes := make([]Route, len(r.routes))
-	copy(routes, r.routes)
+	for i, route := range r.routes {
+		routes[i] = *route
+	}
 
 	sort.Slice(routes, func(i, j int) bool {
 		if routes[i].Method != routes[j].Method {
 			return routes[i].Method < routes[j].Method
 		}
-		return routes[i].Path < routes[j].Path
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		// Variantes d'un même chemin : sans restriction d'abord, puis par hôte et version
+		if routes[i].Host != routes[j].Host {
+			return routes[i].Host < routes[j].Host
+		}
+		return routes[i].Version < routes[j].Version
 	})
 
 	return routes
@@ -492,7 +981,20 @@ func (r *Router) PrintRoutes() {
 	fmt.Println("========================")
 
 	for _, route := range routes {
-		fmt.Printf("%-7s %s\n", route.Method, route.Path)
+		line := fmt.Sprintf("%-7s %s", route.Method, route.Path)
+		if route.Version != "" {
+			line += " [" + route.Version + "]"
+		}
+		if route.Name != "" {
+			line += " (" + route.Name + ")"
+		}
+		if route.Summary != "" {
+			line += " - " + route.Summary
+		}
+		if route.Deprecated {
+			line += " [deprecated]"
+		}
+		fmt.Println(line)
 	}
 	fmt.Println()
 }
@@ -3,8 +3,11 @@ package gofsen
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -335,3 +338,492 @@ func TestCORSFromEnvWithDefaults(t *testing.T) {
 		t.Errorf("Expected CORS origin 'http://localhost:3000', got '%s'", corsOrigin)
 	}
 }
+
+func TestGroupMiddleware(t *testing.T) {
+	app := New()
+
+	var calls []string
+	app.Use(func(c *Context) {
+		calls = append(calls, "global")
+		c.Next()
+	})
+
+	admin := app.Group("/admin")
+	admin.Use(func(c *Context) {
+		calls = append(calls, "group")
+		if c.Request.Header.Get("Authorization") != "Bearer secret" {
+			c.Error(401, "Unauthorized")
+			return
+		}
+		c.Next()
+	})
+	admin.GET("/stats", func(c *Context) {
+		calls = append(calls, "handler")
+		c.JSON(map[string]string{"message": "stats"})
+	})
+
+	app.GET("/public", func(c *Context) {
+		calls = append(calls, "handler")
+		c.JSON(map[string]string{"message": "public"})
+	})
+
+	// Route du groupe sans authentification : le middleware du groupe bloque
+	req := httptest.NewRequest("GET", "/admin/stats", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 401 {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+	if strings.Join(calls, ",") != "global,group" {
+		t.Errorf("Expected calls 'global,group', got '%s'", strings.Join(calls, ","))
+	}
+
+	// Route du groupe authentifiée : global, puis groupe, puis handler
+	calls = nil
+	req = httptest.NewRequest("GET", "/admin/stats", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if strings.Join(calls, ",") != "global,group,handler" {
+		t.Errorf("Expected calls 'global,group,handler', got '%s'", strings.Join(calls, ","))
+	}
+
+	// Route voisine hors groupe : le middleware du groupe ne s'exécute jamais
+	calls = nil
+	req = httptest.NewRequest("GET", "/public", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if strings.Join(calls, ",") != "global,handler" {
+		t.Errorf("Expected calls 'global,handler', got '%s'", strings.Join(calls, ","))
+	}
+}
+
+func TestGroupMiddlewareAddedAfterRoutes(t *testing.T) {
+	app := New()
+
+	admin := app.Group("/admin")
+	admin.GET("/stats", func(c *Context) {
+		c.JSON(map[string]string{"message": "stats"})
+	})
+	admin.Use(func(c *Context) {
+		c.Error(403, "Forbidden")
+	})
+
+	req := httptest.NewRequest("GET", "/admin/stats", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 403 {
+		t.Errorf("Expected status 403, got %d", w.Code)
+	}
+}
+
+func TestNestedGroup(t *testing.T) {
+	app := New()
+
+	var calls []string
+	v1 := app.Group("/api/v1")
+	v1.Use(func(c *Context) {
+		calls = append(calls, "v1")
+		c.Next()
+	})
+	v1.GET("/users", func(c *Context) {
+		calls = append(calls, "users")
+	})
+
+	admin := v1.Group("/admin")
+	admin.Use(func(c *Context) {
+		calls = append(calls, "admin")
+		c.Next()
+	})
+	admin.GET("/stats", func(c *Context) {
+		calls = append(calls, "stats")
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/admin/stats", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if strings.Join(calls, ",") != "v1,admin,stats" {
+		t.Errorf("Expected calls 'v1,admin,stats', got '%s'", strings.Join(calls, ","))
+	}
+
+	// Le middleware du sous-groupe ne s'applique pas au groupe parent
+	calls = nil
+	req = httptest.NewRequest("GET", "/api/v1/users", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if strings.Join(calls, ",") != "v1,users" {
+		t.Errorf("Expected calls 'v1,users', got '%s'", strings.Join(calls, ","))
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	app := New()
+	app.GET("/users/:id", func(c *Context) {})
+	app.DELETE("/users/:id", func(c *Context) {})
+
+	req := httptest.NewRequest("POST", "/users/1", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 405 {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "DELETE, GET, HEAD, OPTIONS" {
+		t.Errorf("Expected Allow header 'DELETE, GET, HEAD, OPTIONS', got '%s'", allow)
+	}
+
+	// Un chemin inconnu reste un 404
+	req = httptest.NewRequest("POST", "/unknown", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 404 {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "" {
+		t.Errorf("Expected no Allow header, got '%s'", allow)
+	}
+}
+
+func TestHeadFallsBackToGet(t *testing.T) {
+	app := New()
+	app.GET("/health", func(c *Context) {
+		c.JSON(map[string]string{"status": "OK"})
+	})
+
+	req := httptest.NewRequest("HEAD", "/health", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected empty body, got '%s'", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type 'application/json', got '%s'", ct)
+	}
+}
+
+func TestExplicitHeadAndOptions(t *testing.T) {
+	app := New()
+	app.GET("/resource", func(c *Context) { c.Text("get") })
+	app.HEAD("/resource", func(c *Context) {
+		c.ResponseWriter.Header().Set("X-Head", "explicit")
+	})
+	app.OPTIONS("/resource", func(c *Context) { c.Text("custom options") })
+
+	req := httptest.NewRequest("HEAD", "/resource", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Header().Get("X-Head") != "explicit" {
+		t.Error("Expected the explicit HEAD handler to run")
+	}
+
+	req = httptest.NewRequest("OPTIONS", "/resource", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Body.String() != "custom options" {
+		t.Errorf("Expected body 'custom options', got '%s'", w.Body.String())
+	}
+}
+
+func TestAutomaticOptions(t *testing.T) {
+	app := New()
+
+	middlewareCalled := false
+	app.Use(func(c *Context) {
+		middlewareCalled = true
+		c.Next()
+	})
+	app.GET("/users/:id", func(c *Context) {})
+	app.PUT("/users/:id", func(c *Context) {})
+
+	req := httptest.NewRequest("OPTIONS", "/users/1", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS, PUT" {
+		t.Errorf("Expected Allow header 'GET, HEAD, OPTIONS, PUT', got '%s'", allow)
+	}
+	if !middlewareCalled {
+		t.Error("Global middleware should run for automatic OPTIONS responses")
+	}
+
+	req = httptest.NewRequest("OPTIONS", "/unknown", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 404 {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
+
+func TestAnyAndHandle(t *testing.T) {
+	app := New()
+	app.Any("/echo", func(c *Context) { c.Text(c.Request.Method) })
+
+	api := app.Group("/api")
+	api.Handle("PROPFIND", "/dav", func(c *Context) { c.Text("dav") })
+
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+		req := httptest.NewRequest(method, "/echo", nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Body.String() != method {
+			t.Errorf("Expected body '%s', got '%s'", method, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest("PROPFIND", "/api/dav", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Body.String() != "dav" {
+		t.Errorf("Expected body 'dav', got '%s'", w.Body.String())
+	}
+}
+
+func TestTypedParams(t *testing.T) {
+	app := New()
+
+	var id int
+	var id64 int64
+	var uuid UUID
+	var errs []error
+	app.GET("/users/:id<int>/files/:file", func(c *Context) {
+		var err error
+		id, err = c.ParamInt("id")
+		errs = append(errs, err)
+		id64, err = c.ParamInt64("id")
+		errs = append(errs, err)
+		uuid, err = c.ParamUUID("file")
+		errs = append(errs, err)
+	})
+
+	req := httptest.NewRequest("GET", "/users/42/files/0B8E5C3A-6D2F-4C1E-9A7B-3F2D1C0E9B8A", nil)
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if id != 42 || id64 != 42 {
+		t.Errorf("Expected id 42, got %d and %d", id, id64)
+	}
+	if uuid.String() != "0b8e5c3a-6d2f-4c1e-9a7b-3f2d1c0e9b8a" {
+		t.Errorf("Expected canonical UUID, got '%s'", uuid.String())
+	}
+
+	c := &Context{Params: map[string]string{"id": "abc"}}
+	if _, err := c.ParamInt("id"); err == nil {
+		t.Error("Expected an error for a non-numeric parameter")
+	}
+	if _, err := c.ParamUUID("id"); err == nil {
+		t.Error("Expected an error for an invalid UUID")
+	}
+	if _, err := c.ParamInt64("missing"); !errors.Is(err, ErrMissingParam) {
+		t.Errorf("Expected ErrMissingParam, got %v", err)
+	}
+}
+
+func TestCustomNotFoundRunsThroughMiddleware(t *testing.T) {
+	app := New()
+	app.Use(CORS())
+
+	var logged []string
+	app.Use(func(c *Context) {
+		c.Next()
+		logged = append(logged, c.Request.URL.Path)
+	})
+	app.NotFound(func(c *Context) {
+		c.Status(404).HTML("<h1>Page introuvable</h1>")
+	})
+	app.GET("/", func(c *Context) {})
+
+	req := httptest.NewRequest("GET", "/missing", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 404 {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	if w.Body.String() != "<h1>Page introuvable</h1>" {
+		t.Errorf("Expected custom body, got '%s'", w.Body.String())
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "http://localhost:3000" {
+		t.Error("Expected CORS headers on not found responses")
+	}
+	if len(logged) != 1 || logged[0] != "/missing" {
+		t.Errorf("Expected the logging middleware to see /missing, got %v", logged)
+	}
+}
+
+func TestCustomMethodNotAllowed(t *testing.T) {
+	app := New()
+
+	middlewareCalled := false
+	app.Use(func(c *Context) {
+		middlewareCalled = true
+		c.Next()
+	})
+	app.MethodNotAllowed(func(c *Context) {
+		c.Status(405).Text("allowed: " + c.ResponseWriter.Header().Get("Allow"))
+	})
+	app.GET("/items", func(c *Context) {})
+
+	req := httptest.NewRequest("DELETE", "/items", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 405 {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+	if w.Body.String() != "allowed: GET, HEAD, OPTIONS" {
+		t.Errorf("Expected body 'allowed: GET, HEAD, OPTIONS', got '%s'", w.Body.String())
+	}
+	if !middlewareCalled {
+		t.Error("Global middleware should run for 405 responses")
+	}
+}
+
+func TestAbortStopsChain(t *testing.T) {
+	app := New()
+
+	var trace []string
+	app.Use(func(c *Context) {
+		trace = append(trace, "outer:before")
+		c.Next()
+		trace = append(trace, fmt.Sprintf("outer:after aborted=%v", c.IsAborted()))
+		c.Next() // sans effet : la chaîne est interrompue
+	})
+
+	api := app.Group("/api")
+	api.Use(func(c *Context) {
+		trace = append(trace, "auth")
+		if c.Request.Header.Get("Authorization") == "" {
+			c.AbortWithStatus(401)
+		}
+		c.Next()
+	})
+	api.GET("/private", func(c *Context) {
+		trace = append(trace, "handler")
+		c.Text("secret")
+	})
+
+	req := httptest.NewRequest("GET", "/api/private", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 401 {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+	want := []string{"outer:before", "auth", "outer:after aborted=true"}
+	if strings.Join(trace, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Expected trace %v, got %v", want, trace)
+	}
+
+	trace = nil
+	req = httptest.NewRequest("GET", "/api/private", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	want = []string{"outer:before", "auth", "handler", "outer:after aborted=false"}
+	if strings.Join(trace, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Expected trace %v, got %v", want, trace)
+	}
+	if w.Body.String() != "secret" {
+		t.Errorf("Expected body 'secret', got '%s'", w.Body.String())
+	}
+}
+
+func TestAbortNestedNext(t *testing.T) {
+	app := New()
+
+	var trace []string
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		app.Use(func(c *Context) {
+			trace = append(trace, name+">")
+			c.Next()
+			trace = append(trace, fmt.Sprintf("<%s:%v", name, c.IsAborted()))
+		})
+	}
+	app.GET("/items", func(c *Context) {
+		trace = append(trace, "handler")
+		c.Text("done")
+		c.Abort() // après écriture : n'affecte que les middlewares en amont
+	})
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	want := "a>, b>, c>, handler, <c:true, <b:true, <a:true"
+	if got := strings.Join(trace, ", "); got != want {
+		t.Errorf("Expected trace '%s', got '%s'", want, got)
+	}
+	if w.Code != 200 || w.Body.String() != "done" {
+		t.Errorf("Expected 200 'done', got %d '%s'", w.Code, w.Body.String())
+	}
+}
+
+func TestAbortWithError(t *testing.T) {
+	app := New()
+
+	var observed error
+	app.Use(func(c *Context) {
+		c.Next()
+		observed = c.Err()
+	})
+	app.Use(func(c *Context) {
+		if c.QueryParam("token") != "ok" {
+			c.AbortWithError(403, errors.New("invalid token"))
+		}
+		c.Next()
+	})
+	handlerCalled := false
+	app.GET("/admin", func(c *Context) { handlerCalled = true })
+
+	req := httptest.NewRequest("GET", "/admin", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 403 {
+		t.Errorf("Expected status 403, got %d", w.Code)
+	}
+	if handlerCalled {
+		t.Error("Handler should not run after AbortWithError")
+	}
+	if observed == nil || !strings.Contains(observed.Error(), "invalid token") {
+		t.Errorf("Expected observed error to carry the cause, got %v", observed)
+	}
+	if strings.Contains(w.Body.String(), "invalid token") {
+		t.Errorf("Expected cause to stay private, got %s", w.Body.String())
+	}
+}
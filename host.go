@@ -0,0 +1,115 @@
+package gofsen
+
+import "strings"
+
+// hostRouter regroupe les arbres de routage propres à un motif d'hôte,
+// ex: "api.example.com" ou ":tenant.example.com"
+type hostRouter struct {
+	pattern  string
+	segments []string
+	params   []string
+	trees    map[string]*node
+}
+
+// Host retourne un groupe dont les routes ne répondent que si l'en-tête Host
+// correspond à pattern. Un segment ":nom" capture un label du nom d'hôte,
+// exposé dans Context.Params comme un paramètre de route.
+func (r *Router) Host(pattern string) *RouteGroup {
+	pattern = normalizeHostPattern(pattern)
+
+	var host *hostRouter
+	for _, h := range r.hosts {
+		if h.pattern == pattern {
+			host = h
+			break
+		}
+	}
+	if host == nil {
+		host = newHostRouter(pattern)
+		r.addHost(host)
+	}
+
+	return &RouteGroup{
+		router: r,
+		host:   host,
+	}
+}
+
+// normalizeHostPattern met en minuscules les labels littéraux du motif, les
+// noms de paramètres ":nom" restant tels qu'écrits
+func normalizeHostPattern(pattern string) string {
+	segments := strings.Split(pattern, ".")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			segments[i] = strings.ToLower(segment)
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+func newHostRouter(pattern string) *hostRouter {
+	host := &hostRouter{
+		pattern:  pattern,
+		segments: strings.Split(pattern, "."),
+		trees:    make(map[string]*node),
+	}
+	for _, segment := range host.segments {
+		if strings.HasPrefix(segment, ":") {
+			if len(segment) == 1 {
+				panic("gofsen: host parameter must be named in '" + pattern + "'")
+			}
+			host.params = append(host.params, segment[1:])
+		}
+	}
+	return host
+}
+
+// addHost enregistre le motif, les hôtes sans paramètre étant essayés en premier
+func (r *Router) addHost(host *hostRouter) {
+	if len(host.params) > 0 {
+		r.hosts = append(r.hosts, host)
+		return
+	}
+
+	i := 0
+	for i < len(r.hosts) && len(r.hosts[i].params) == 0 {
+		i++
+	}
+	r.hosts = append(r.hosts, nil)
+	copy(r.hosts[i+1:], r.hosts[i:])
+	r.hosts[i] = host
+}
+
+// match compare un nom d'hôte (sans port) au motif et retourne les valeurs capturées
+func (h *hostRouter) match(host string) ([]string, bool) {
+	if strings.Count(host, ".")+1 != len(h.segments) {
+		return nil, false
+	}
+
+	var values []string
+	for _, segment := range h.segments {
+		label := host
+		if i := strings.IndexByte(host, '.'); i >= 0 {
+			label, host = host[:i], host[i+1:]
+		}
+
+		if strings.HasPrefix(segment, ":") {
+			if label == "" {
+				return nil, false
+			}
+			values = append(values, label)
+		} else if !strings.EqualFold(segment, label) {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// stripPort retire le port éventuel d'un en-tête Host, y compris pour une adresse IPv6
+func stripPort(host string) string {
+	i := strings.LastIndexByte(host, ':')
+	if i < 0 || i < strings.LastIndexByte(host, ']') {
+		return host
+	}
+	return host[:i]
+}
@@ -0,0 +1,98 @@
+package gofsen
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostRouting(t *testing.T) {
+	app := New()
+
+	api := app.Host("api.example.com")
+	api.GET("/status", func(c *Context) { c.Text("api") })
+
+	admin := app.Host("admin.example.com")
+	admin.GET("/status", func(c *Context) { c.Text("admin") })
+
+	tenants := app.Host(":tenant.example.com")
+	tenants.Group("/v1").GET("/users/:id", func(c *Context) {
+		c.Text(c.Param("tenant") + "/" + c.Param("id"))
+	})
+
+	app.GET("/status", func(c *Context) { c.Text("default") })
+
+	tests := []struct {
+		host string
+		path string
+		code int
+		body string
+	}{
+		{"api.example.com", "/status", 200, "api"},
+		{"API.example.com:8080", "/status", 200, "api"},
+		{"admin.example.com", "/status", 200, "admin"},
+		{"other.com", "/status", 200, "default"},
+		{"acme.example.com", "/v1/users/42", 200, "acme/42"},
+		{"acme.example.com", "/status", 200, "default"},
+		{"api.example.com", "/v1/users/42", 200, "api/42"},
+		{"example.com", "/v1/users/42", 404, ""},
+		{"a.b.example.com", "/v1/users/42", 404, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Host = tt.host
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s%s: expected status %d, got %d", tt.host, tt.path, tt.code, w.Code)
+			continue
+		}
+		if tt.code == 200 && w.Body.String() != tt.body {
+			t.Errorf("%s%s: expected body '%s', got '%s'", tt.host, tt.path, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestHostMethodNotAllowed(t *testing.T) {
+	app := New()
+	app.Host("api.example.com").POST("/items", func(c *Context) {})
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	req.Host = "api.example.com"
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 405 {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/items", nil)
+	req.Host = "www.example.com"
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 404 {
+		t.Errorf("Expected status 404 for another host, got %d", w.Code)
+	}
+}
+
+func TestHostParamCase(t *testing.T) {
+	app := New()
+	app.Host(":tenantID.Example.COM").GET("/", func(c *Context) { c.Text(c.Param("tenantID")) })
+	app.Host(":tenantID.example.com").GET("/me", func(c *Context) { c.Text("me " + c.Param("tenantID")) })
+
+	for path, body := range map[string]string{"/": "Acme", "/me": "me Acme"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "Acme.example.com"
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != 200 || w.Body.String() != body {
+			t.Errorf("%s: expected 200 '%s', got %d '%s'", path, body, w.Code, w.Body.String())
+		}
+	}
+	if len(app.hosts) != 1 {
+		t.Errorf("Expected literal labels to be case-insensitive, got %d hosts", len(app.hosts))
+	}
+}
@@ -0,0 +1,95 @@
+package gofsen
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Mount branche un http.Handler quelconque (net/http/pprof, Prometheus, autre
+// Router Gofsen...) sous prefix. Le préfixe est retiré du chemin avant l'appel,
+// et les middlewares globaux s'appliquent comme pour une route classique.
+// Les routes créées sont exclues du document OpenAPI.
+func (r *Router) Mount(prefix string, handler http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	mounted := mountHandler(prefix, handler)
+	for _, method := range anyMethods {
+		if prefix != "" {
+			r.addRoute(method, prefix, mounted).hidden = true
+		}
+		r.addRoute(method, prefix+"/*", mounted).hidden = true
+	}
+}
+
+// Mount branche un http.Handler sous le préfixe du groupe, avec ses middlewares
+func (g *RouteGroup) Mount(prefix string, handler http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	mounted := mountHandler(g.prefix+prefix, handler)
+	for _, method := range anyMethods {
+		if g.prefix+prefix != "" {
+			g.addRoute(method, prefix, mounted).hidden = true
+		}
+		g.addRoute(method, prefix+"/*", mounted).hidden = true
+	}
+}
+
+// mountHandler appelle handler avec une copie de la requête sans le préfixe
+func mountHandler(prefix string, handler http.Handler) HandlerFunc {
+	return func(c *Context) {
+		req := c.Request
+
+		path := strings.TrimPrefix(req.URL.Path, prefix)
+		if path == "" {
+			path = "/"
+		}
+		rawPath := ""
+		if req.URL.RawPath != "" {
+			rawPath = strings.TrimPrefix(req.URL.RawPath, prefix)
+			if rawPath == "" {
+				rawPath = "/"
+			}
+		}
+
+		stripped := new(http.Request)
+		*stripped = *req
+		stripped.URL = new(url.URL)
+		*stripped.URL = *req.URL
+		stripped.URL.Path = path
+		stripped.URL.RawPath = rawPath
+
+		handler.ServeHTTP(c.ResponseWriter, stripped)
+	}
+}
+
+// WrapF convertit une fonction handler standard en HandlerFunc Gofsen
+func WrapF(f func(http.ResponseWriter, *http.Request)) HandlerFunc {
+	return func(c *Context) {
+		f(c.ResponseWriter, c.Request)
+	}
+}
+
+// WrapH convertit un http.Handler en HandlerFunc Gofsen
+func WrapH(h http.Handler) HandlerFunc {
+	return func(c *Context) {
+		h.ServeHTTP(c.ResponseWriter, c.Request)
+	}
+}
+
+// WrapMiddleware convertit un middleware net/http standard en MiddlewareFunc Gofsen.
+// Le ResponseWriter et la requête transmis par le middleware sont visibles par la
+// suite de la chaîne, puis restaurés une fois celle-ci terminée.
+func WrapMiddleware(m func(http.Handler) http.Handler) MiddlewareFunc {
+	return func(c *Context) {
+		w, req := c.ResponseWriter, c.Request
+		defer func() {
+			c.ResponseWriter, c.Request = w, req
+		}()
+
+		next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			c.ResponseWriter = w
+			c.Request = req
+			c.Next()
+		})
+		m(next).ServeHTTP(w, req)
+	}
+}
@@ -0,0 +1,101 @@
+package gofsen
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMountHandler(t *testing.T) {
+	app := New()
+
+	middlewareCalled := false
+	app.Use(func(c *Context) {
+		middlewareCalled = true
+		c.Next()
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("metrics:" + r.URL.Path))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("root:" + r.URL.Path))
+	})
+	app.Mount("/ops/", mux)
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/ops/metrics", "metrics:/metrics"},
+		{"/ops", "root:/"},
+		{"/ops/", "root:/"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Body.String() != tt.body {
+			t.Errorf("%s: expected body '%s', got '%s'", tt.path, tt.body, w.Body.String())
+		}
+	}
+	if !middlewareCalled {
+		t.Error("Global middleware should run for mounted handlers")
+	}
+}
+
+func TestMountSubRouter(t *testing.T) {
+	billing := New()
+	billing.GET("/invoices/:id", func(c *Context) {
+		c.Text("invoice " + c.Param("id"))
+	})
+
+	app := New()
+	api := app.Group("/api")
+	api.Mount("/billing", billing)
+
+	req := httptest.NewRequest("GET", "/api/billing/invoices/12", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Body.String() != "invoice 12" {
+		t.Errorf("Expected body 'invoice 12', got '%s'", w.Body.String())
+	}
+}
+
+func TestWrapAdapters(t *testing.T) {
+	app := New()
+
+	app.Use(WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Std-Middleware", "yes")
+			next.ServeHTTP(w, r)
+		})
+	}))
+	app.GET("/f", WrapF(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("func"))
+	}))
+	app.GET("/h", WrapH(http.NotFoundHandler()))
+
+	req := httptest.NewRequest("GET", "/f", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Body.String() != "func" {
+		t.Errorf("Expected body 'func', got '%s'", w.Body.String())
+	}
+	if w.Header().Get("X-Std-Middleware") != "yes" {
+		t.Error("Expected the wrapped net/http middleware to run")
+	}
+
+	req = httptest.NewRequest("GET", "/h", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 404 {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,474 @@
+package gofsen
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIConfig décrit les informations générales du document OpenAPI
+type OpenAPIConfig struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []string
+}
+
+// OpenAPIDocument est un document OpenAPI 3.1 généré depuis les routes du router
+type OpenAPIDocument map[string]interface{}
+
+// openAPIMethods liste les méthodes qui peuvent décrire une opération OpenAPI
+var openAPIMethods = map[string]bool{
+	"GET": true, "PUT": true, "POST": true, "DELETE": true,
+	"OPTIONS": true, "HEAD": true, "PATCH": true, "TRACE": true,
+}
+
+// Accepts documente le type Go du corps JSON attendu par la route
+func (route *Route) Accepts(body interface{}) *Route {
+	route.requestBody = reflect.TypeOf(body)
+	return route
+}
+
+// Returns documente une réponse de la route et, si body n'est pas nil, le type Go de son corps JSON
+func (route *Route) Returns(status int, body interface{}) *Route {
+	if route.responses == nil {
+		route.responses = make(map[int]reflect.Type)
+	}
+	route.responses[status] = reflect.TypeOf(body)
+	return route
+}
+
+// OpenAPI génère le document OpenAPI 3.1 des routes enregistrées. Quand une
+// même méthode et un même chemin existent pour plusieurs hôtes ou versions,
+// les variantes sont triées par hôte puis par version (la route sans
+// restriction en premier) : l'opération décrite est celle de la première, et
+// l'extension x-variants liste l'hôte et la version de chacune.
+func (r *Router) OpenAPI(config OpenAPIConfig) OpenAPIDocument {
+	if config.Title == "" {
+		config.Title = "Gofsen API"
+	}
+	if config.Version == "" {
+		config.Version = "1.0.0"
+	}
+
+	info := map[string]interface{}{
+		"title":   config.Title,
+		"version": config.Version,
+	}
+	if config.Description != "" {
+		info["description"] = config.Description
+	}
+
+	doc := OpenAPIDocument{
+		"openapi": "3.1.0",
+		"info":    info,
+	}
+	if len(config.Servers) > 0 {
+		servers := make([]interface{}, 0, len(config.Servers))
+		for _, server := range config.Servers {
+			servers = append(servers, map[string]interface{}{"url": server})
+		}
+		doc["servers"] = servers
+	}
+
+	schemas := make(map[string]interface{})
+	paths := make(map[string]interface{})
+	kept := make(map[string]Route)
+	for _, route := range r.Routes() {
+		if route.hidden || !openAPIMethods[route.Method] {
+			continue
+		}
+
+		path, parameters := openAPIPath(route.Path)
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+		method := strings.ToLower(route.Method)
+		if operation, exists := item[method].(map[string]interface{}); exists {
+			// Variante d'hôte ou de version : listée sur l'opération retenue
+			variants, _ := operation["x-variants"].([]interface{})
+			if variants == nil {
+				first := kept[method+" "+path]
+				variants = []interface{}{openAPIVariant(&first)}
+			}
+			operation["x-variants"] = append(variants, openAPIVariant(&route))
+			continue
+		}
+		item[method] = openAPIOperation(&route, parameters, schemas)
+		kept[method+" "+path] = route
+	}
+	doc["paths"] = paths
+	if len(schemas) > 0 {
+		doc["components"] = map[string]interface{}{"schemas": schemas}
+	}
+
+	return doc
+}
+
+// ServeOpenAPI expose le document OpenAPI sur path, en YAML si path se termine
+// par .yaml ou .yml, en JSON sinon. Le document est régénéré à chaque requête.
+func (r *Router) ServeOpenAPI(path string, config OpenAPIConfig) {
+	asYAML := strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+	route := r.GET(path, func(c *Context) {
+		doc := r.OpenAPI(config)
+
+		var data []byte
+		var err error
+		if asYAML {
+			c.ResponseWriter.Header().Set("Content-Type", "application/yaml")
+			data, err = doc.YAML()
+		} else {
+			c.ResponseWriter.Header().Set("Content-Type", "application/json")
+			data, err = doc.JSON()
+		}
+		if err != nil {
+			c.Error(500, err.Error())
+			return
+		}
+		c.ResponseWriter.Write(data)
+	})
+	route.hidden = true
+}
+
+// JSON encode le document en JSON indenté
+func (d OpenAPIDocument) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// YAML encode le document en YAML
+func (d OpenAPIDocument) YAML() ([]byte, error) {
+	// Passer par JSON normalise le document en maps, slices et scalaires
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	writeYAML(&b, generic, 0)
+	return []byte(b.String()), nil
+}
+
+// WriteFile exporte le document dans un fichier, en YAML pour les extensions .yaml/.yml
+func (d OpenAPIDocument) WriteFile(filename string) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		data, err = d.YAML()
+	default:
+		data, err = d.JSON()
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o644)
+}
+
+// openAPIPath convertit un motif Gofsen (/users/:id<int>) en chemin OpenAPI
+// (/users/{id}) et retourne les paramètres de chemin correspondants
+func openAPIPath(pattern string) (string, []interface{}) {
+	var b strings.Builder
+	var parameters []interface{}
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case ':':
+			name, constraint, end := paramToken(pattern[i:])
+			b.WriteString("{" + name + "}")
+			parameters = append(parameters, map[string]interface{}{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   constraintSchema(constraint),
+			})
+			i += end - 1
+		case '*':
+			name := pattern[i+1:]
+			if name == "" {
+				name = "path"
+			}
+			b.WriteString("{" + name + "}")
+			parameters = append(parameters, map[string]interface{}{
+				"name":        name,
+				"in":          "path",
+				"required":    true,
+				"description": "Remaining path, may contain slashes",
+				"schema":      map[string]interface{}{"type": "string"},
+			})
+			i = len(pattern)
+		default:
+			b.WriteByte(pattern[i])
+		}
+	}
+	return b.String(), parameters
+}
+
+// constraintSchema traduit une contrainte de paramètre en schéma JSON
+func constraintSchema(constraint string) map[string]interface{} {
+	switch constraint {
+	case "":
+		return map[string]interface{}{"type": "string"}
+	case "int":
+		return map[string]interface{}{"type": "integer"}
+	case "uuid":
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	}
+	return map[string]interface{}{"type": "string", "pattern": "^(?:" + constraint + ")$"}
+}
+
+// openAPIVariant décrit l'hôte et la version d'une route pour x-variants
+func openAPIVariant(route *Route) map[string]interface{} {
+	variant := make(map[string]interface{})
+	if route.Host != "" {
+		variant["host"] = route.Host
+	}
+	if route.Version != "" {
+		variant["version"] = route.Version
+	}
+	return variant
+}
+
+// openAPIOperation construit l'opération OpenAPI d'une route
+func openAPIOperation(route *Route, parameters []interface{}, schemas map[string]interface{}) map[string]interface{} {
+	operation := make(map[string]interface{})
+	if route.Name != "" {
+		operation["operationId"] = route.Name
+	}
+	if route.Summary != "" {
+		operation["summary"] = route.Summary
+	}
+	if len(route.Tags) > 0 {
+		operation["tags"] = route.Tags
+	}
+	if route.Deprecated {
+		operation["deprecated"] = true
+	}
+	for key, value := range route.Metadata {
+		operation["x-"+key] = value
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if route.requestBody != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": schemaFor(route.requestBody, schemas),
+				},
+			},
+		}
+	}
+
+	responses := make(map[string]interface{})
+	for status, body := range route.responses {
+		response := map[string]interface{}{"description": http.StatusText(status)}
+		if body != nil {
+			response["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": schemaFor(body, schemas),
+				},
+			}
+		}
+		responses[strconv.Itoa(status)] = response
+	}
+	if len(responses) == 0 {
+		responses["200"] = map[string]interface{}{"description": "OK"}
+	}
+	operation["responses"] = responses
+
+	return operation
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor construit le schéma JSON d'un type Go. Les structs nommées sont
+// enregistrées dans components/schemas et référencées par $ref.
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		name := t.Name()
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+		if _, ok := schemas[name]; ok {
+			return ref
+		}
+		// Réserver le nom avant de descendre pour supporter les types récursifs
+		schemas[name] = map[string]interface{}{}
+		schemas[name] = structSchema(t, schemas)
+		return ref
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema construit le schéma d'une struct en suivant les tags json
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	collectFields(t, schemas, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// collectFields ajoute les champs exportés de t, y compris ceux des structs embarquées
+func collectFields(t reflect.Type, schemas, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectFields(embedded, schemas, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaFor(field.Type, schemas)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// writeYAML écrit une valeur JSON générique (maps, slices, scalaires) en YAML bloc
+func writeYAML(b *strings.Builder, value interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			b.WriteString(pad + yamlKey(key) + ":")
+			writeYAMLChild(b, v[key], indent)
+		}
+	case []interface{}:
+		for _, item := range v {
+			if scalar, ok := yamlScalar(item); ok {
+				b.WriteString(pad + "- " + scalar + "\n")
+				continue
+			}
+			// L'élément est écrit avec une indentation de plus, puis son
+			// premier niveau est remonté derrière le tiret
+			var nested strings.Builder
+			writeYAML(&nested, item, indent+2)
+			b.WriteString(pad + "- " + strings.TrimPrefix(nested.String(), pad+"  "))
+		}
+	}
+}
+
+// writeYAMLChild écrit la valeur d'une clé : en ligne si elle est scalaire ou vide, en bloc sinon
+func writeYAMLChild(b *strings.Builder, value interface{}, indent int) {
+	if scalar, ok := yamlScalar(value); ok {
+		b.WriteString(" " + scalar + "\n")
+		return
+	}
+	b.WriteString("\n")
+	writeYAML(b, value, indent+2)
+}
+
+// yamlScalar formate une valeur scalaire ou une collection vide
+func yamlScalar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "null", true
+	case bool:
+		return strconv.FormatBool(v), true
+	case json.Number:
+		return v.String(), true
+	case string:
+		return strconv.Quote(v), true
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}", true
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]", true
+		}
+	}
+	return "", false
+}
+
+// yamlKey laisse les clés simples telles quelles et met les autres entre guillemets
+func yamlKey(key string) string {
+	switch strings.ToLower(key) {
+	case "", "true", "false", "null", "yes", "no", "on", "off", "y", "n":
+		return strconv.Quote(key)
+	}
+	for i, c := range key {
+		simple := c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+			i > 0 && (c >= '0' && c <= '9' || c == '-' || c == '.')
+		if !simple {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
@@ -0,0 +1,212 @@
+package gofsen
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type apiAddress struct {
+	City string `json:"city"`
+}
+
+type apiUser struct {
+	ID        int         `json:"id"`
+	Name      string      `json:"name"`
+	Email     string      `json:"email,omitempty"`
+	Address   *apiAddress `json:"address"`
+	Friends   []apiUser   `json:"friends,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	password  string
+	Internal  string `json:"-"`
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	app := New()
+	app.GET("/users/:id<int>", func(c *Context) {}).
+		Named("getUser").
+		Describe("Get a user").
+		Tag("users").
+		Annotate("auth", "bearer").
+		Returns(200, apiUser{}).
+		Returns(404, nil)
+	app.POST("/users", func(c *Context) {}).
+		Accepts(apiUser{}).
+		Returns(201, &apiUser{})
+	app.GET("/files/*filepath", func(c *Context) {}).Deprecate()
+
+	doc := app.OpenAPI(OpenAPIConfig{Title: "Users", Version: "2.0.0", Servers: []string{"https://api.example.com"}})
+
+	data, err := doc.JSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if spec.OpenAPI != "3.1.0" || spec.Info.Title != "Users" || spec.Info.Version != "2.0.0" {
+		t.Errorf("Unexpected header: %+v", spec)
+	}
+
+	getUser, ok := spec.Paths["/users/{id}"]["get"]
+	if !ok {
+		t.Fatalf("Expected GET /users/{id}, got paths %v", spec.Paths)
+	}
+	if getUser["operationId"] != "getUser" || getUser["summary"] != "Get a user" || getUser["x-auth"] != "bearer" {
+		t.Errorf("Unexpected operation: %v", getUser)
+	}
+	params := getUser["parameters"].([]interface{})
+	param := params[0].(map[string]interface{})
+	if param["name"] != "id" || param["in"] != "path" || param["schema"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("Unexpected parameter: %v", param)
+	}
+	responses := getUser["responses"].(map[string]interface{})
+	if _, ok := responses["404"]; !ok {
+		t.Errorf("Expected a 404 response, got %v", responses)
+	}
+
+	if _, ok := spec.Paths["/users"]["post"]["requestBody"]; !ok {
+		t.Error("Expected a request body on POST /users")
+	}
+	if spec.Paths["/files/{filepath}"]["get"]["deprecated"] != true {
+		t.Error("Expected GET /files/{filepath} to be deprecated")
+	}
+
+	user, ok := spec.Components.Schemas["apiUser"]
+	if !ok {
+		t.Fatalf("Expected an apiUser schema, got %v", spec.Components.Schemas)
+	}
+	properties := user["properties"].(map[string]interface{})
+	for _, name := range []string{"id", "name", "email", "address", "friends", "created_at"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("Expected property %s in %v", name, properties)
+		}
+	}
+	for _, name := range []string{"password", "Internal"} {
+		if _, ok := properties[name]; ok {
+			t.Errorf("Unexpected property %s", name)
+		}
+	}
+	required := user["required"].([]interface{})
+	if len(required) != 3 {
+		t.Errorf("Expected required [created_at id name], got %v", required)
+	}
+	if _, ok := spec.Components.Schemas["apiAddress"]; !ok {
+		t.Error("Expected an apiAddress schema")
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	app := New()
+	app.GET("/users/:id<int>", func(c *Context) {})
+	app.ServeOpenAPI("/openapi.json", OpenAPIConfig{Title: "Users"})
+	app.ServeOpenAPI("/openapi.yaml", OpenAPIConfig{Title: "Users"})
+
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got '%s'", ct)
+	}
+	if strings.Contains(w.Body.String(), "/openapi.json") {
+		t.Error("The documentation route should not document itself")
+	}
+
+	req = httptest.NewRequest("GET", "/openapi.yaml", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	if ct := w.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("Expected application/yaml, got '%s'", ct)
+	}
+	for _, want := range []string{
+		"openapi: \"3.1.0\"\n",
+		"  \"/users/{id}\":\n    get:\n",
+		"      parameters:\n        - in: \"path\"\n          name: \"id\"\n",
+		"      responses:\n        \"200\":\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected YAML to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestOpenAPIHiddenRoutes(t *testing.T) {
+	app := New()
+	app.GET("/users", func(c *Context) { c.JSON([]string{}) })
+	app.Mount("/debug", http.NotFoundHandler())
+	app.Group("/admin").Mount("/metrics", http.NotFoundHandler())
+	app.Static("/assets", t.TempDir())
+
+	paths := app.OpenAPI(OpenAPIConfig{})["paths"].(map[string]interface{})
+	if len(paths) != 1 || paths["/users"] == nil {
+		t.Errorf("Expected only /users in the document, got %v", paths)
+	}
+}
+
+func TestOpenAPIVariants(t *testing.T) {
+	app := New()
+	app.SetVersioning(VersionConfig{Default: "v2"})
+	app.Version("v2").GET("/items", func(c *Context) {}).Summary = "v2 items"
+	app.Version("v1").GET("/items", func(c *Context) {}).Summary = "v1 items"
+	app.GET("/items", func(c *Context) {}).Summary = "items"
+	app.Host("api.example.com").GET("/items", func(c *Context) {})
+
+	for i := 0; i < 5; i++ {
+		doc := app.OpenAPI(OpenAPIConfig{})
+		get := doc["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})
+
+		if get["summary"] != "items" {
+			t.Fatalf("Expected the unrestricted route to be described, got %v", get["summary"])
+		}
+		want := []interface{}{
+			map[string]interface{}{},
+			map[string]interface{}{"version": "v1"},
+			map[string]interface{}{"version": "v2"},
+			map[string]interface{}{"host": "api.example.com"},
+		}
+		if !reflect.DeepEqual(get["x-variants"], want) {
+			t.Fatalf("Expected variants %v, got %v", want, get["x-variants"])
+		}
+	}
+}
+
+func TestOpenAPIWriteFile(t *testing.T) {
+	app := New()
+	app.GET("/users/:id<int>", func(c *Context) {}).Returns(200, apiUser{})
+
+	doc := app.OpenAPI(OpenAPIConfig{})
+	dir := t.TempDir()
+
+	for _, name := range []string{"openapi.json", "openapi.yml"} {
+		filename := filepath.Join(dir, name)
+		if err := doc.WriteFile(filename); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(filename)
+		if err != nil || len(data) == 0 {
+			t.Errorf("%s: expected a non-empty file, got %v", name, err)
+		}
+	}
+}
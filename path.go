@@ -0,0 +1,134 @@
+package gofsen
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PathMode définit le traitement des chemins non canoniques : slash final
+// en trop ou manquant, segments "." et "..", slashs dupliqués
+type PathMode int
+
+const (
+	// PathStrict compare le chemin tel quel : /users/ ne trouve pas /users (404)
+	PathStrict PathMode = iota
+	// PathRedirect redirige vers la forme canonique (301 pour GET/HEAD, 308 sinon)
+	PathRedirect
+	// PathLenient sert directement la route de la forme canonique, sans redirection
+	PathLenient
+)
+
+// SetPathMode configure le traitement des chemins non canoniques (PathStrict par défaut)
+func (r *Router) SetPathMode(mode PathMode) {
+	r.pathMode = mode
+}
+
+// routingPath retourne le chemin utilisé pour le routage. Si la requête contient
+// des caractères encodés significatifs (ex: %2F), on route sur URL.RawPath pour
+// qu'ils ne soient pas pris pour des séparateurs, et raw vaut true.
+func routingPath(u *url.URL) (path string, raw bool) {
+	if u.RawPath != "" && u.RawPath != u.Path {
+		return u.RawPath, true
+	}
+	return u.Path, false
+}
+
+// fixPath cherche une forme canonique du chemin qui correspond à une route :
+// le chemin nettoyé, puis ce même chemin avec ou sans slash final
+func (r *Router) fixPath(host string, version *versionRouter, method, path string) string {
+	for _, candidate := range pathCandidates(path) {
+		if route, _, _ := r.lookup(host, version, method, candidate, false); route != nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// fixAllowedMethods retourne les méthodes autorisées de la première forme
+// canonique du chemin qui en a, pour répondre 405 plutôt que 404 à POST /users/
+// quand seul GET /users existe
+func (r *Router) fixAllowedMethods(host string, version *versionRouter, path string) []string {
+	for _, candidate := range pathCandidates(path) {
+		if allowed := r.allowedMethods(host, version, candidate); len(allowed) > 0 {
+			return allowed
+		}
+	}
+	return nil
+}
+
+// pathCandidates retourne les formes canoniques à essayer pour un chemin, dans
+// l'ordre : le chemin nettoyé, puis ce même chemin avec ou sans slash final
+func pathCandidates(path string) []string {
+	clean := cleanPath(path)
+	candidates := []string{clean}
+	if strings.HasSuffix(clean, "/") {
+		if clean != "/" {
+			candidates = append(candidates, clean[:len(clean)-1])
+		}
+	} else {
+		candidates = append(candidates, clean+"/")
+	}
+
+	out := candidates[:0]
+	for _, candidate := range candidates {
+		if candidate != path {
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
+
+// redirectHandler redirige vers le chemin canonique path
+func redirectHandler(path string, raw bool) HandlerFunc {
+	return func(c *Context) {
+		redirectPath(c.ResponseWriter, c.Request, path, raw)
+	}
+}
+
+// redirectPath redirige vers le chemin canonique en conservant la query string
+func redirectPath(w http.ResponseWriter, req *http.Request, path string, raw bool) {
+	location := path
+	if !raw {
+		location = (&url.URL{Path: path}).EscapedPath()
+	}
+	if req.URL.RawQuery != "" {
+		location += "?" + req.URL.RawQuery
+	}
+
+	code := http.StatusPermanentRedirect
+	if req.Method == "GET" || req.Method == "HEAD" {
+		code = http.StatusMovedPermanently
+	}
+	w.Header().Set("Location", location)
+	w.WriteHeader(code)
+}
+
+// cleanPath retourne la forme canonique d'un chemin d'URL : un seul slash entre
+// les segments, sans "." ni "..", avec un slash initial et en conservant le slash final
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	segments := strings.Split(p, "/")
+	out := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		switch segment {
+		case "", ".":
+		case "..":
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		default:
+			out = append(out, segment)
+		}
+	}
+
+	clean := "/" + strings.Join(out, "/")
+	last := segments[len(segments)-1]
+	if clean != "/" && (last == "" || last == "." || last == "..") {
+		clean += "/"
+	}
+	return clean
+}
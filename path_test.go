@@ -0,0 +1,191 @@
+package gofsen
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"/users", "/users"},
+		{"/users/", "/users/"},
+		{"//users//1", "/users/1"},
+		{"/a/../users", "/users"},
+		{"/a/./b/", "/a/b/"},
+		{"/a/b/..", "/a/"},
+		{"/../../x", "/x"},
+		{"users", "/users"},
+	}
+
+	for _, tt := range tests {
+		if got := cleanPath(tt.path); got != tt.want {
+			t.Errorf("cleanPath(%q): expected %q, got %q", tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestPathModeStrict(t *testing.T) {
+	app := New()
+	app.GET("/users", func(c *Context) { c.Text("users") })
+	app.GET("/docs/", func(c *Context) { c.Text("docs") })
+
+	for _, path := range []string{"/users/", "//users", "/a/../users", "/docs"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != 404 {
+			t.Errorf("%s: expected status 404, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestPathModeRedirect(t *testing.T) {
+	app := New()
+	app.SetPathMode(PathRedirect)
+	app.GET("/users", func(c *Context) { c.Text("users") })
+	app.GET("/users/:id", func(c *Context) { c.Text("user " + c.Param("id")) })
+	app.POST("/users", func(c *Context) { c.Text("created") })
+	app.GET("/docs/", func(c *Context) { c.Text("docs") })
+
+	tests := []struct {
+		method   string
+		path     string
+		query    string
+		code     int
+		location string
+	}{
+		{"GET", "/users/", "", 301, "/users"},
+		{"GET", "/users//1", "", 301, "/users/1"},
+		{"GET", "/a/../users", "page=2", 301, "/users?page=2"},
+		{"GET", "/docs", "", 301, "/docs/"},
+		{"GET", "//users", "", 301, "/users"},
+		{"POST", "/users/", "", 308, "/users"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/", nil)
+		req.URL.Path = tt.path
+		req.URL.RawQuery = tt.query
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.code, w.Code)
+		}
+		if location := w.Header().Get("Location"); location != tt.location {
+			t.Errorf("%s %s: expected Location '%s', got '%s'", tt.method, tt.path, tt.location, location)
+		}
+	}
+}
+
+func TestPathModeLenient(t *testing.T) {
+	app := New()
+	app.SetPathMode(PathLenient)
+	app.GET("/users", func(c *Context) { c.Text("users") })
+	app.GET("/users/:id", func(c *Context) { c.Text("user " + c.Param("id")) })
+	app.GET("/docs/", func(c *Context) { c.Text("docs") })
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/users/", "users"},
+		{"/users//7", "user 7"},
+		{"/x/../users/7/", "user 7"},
+		{"/docs", "docs"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.URL.Path = tt.path
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != 200 {
+			t.Errorf("%s: expected status 200, got %d", tt.path, w.Code)
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("%s: expected body '%s', got '%s'", tt.path, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestRawPathParams(t *testing.T) {
+	app := New()
+	app.GET("/files/:name", func(c *Context) { c.Text(c.Param("name")) })
+	app.GET("/files/:name/meta", func(c *Context) { c.Text("meta " + c.Param("name")) })
+
+	tests := []struct {
+		target string
+		body   string
+	}{
+		{"/files/a%2Fb", "a/b"},
+		{"/files/a%2Fb/meta", "meta a/b"},
+		{"/files/hello%20world", "hello world"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.target, nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != 200 {
+			t.Errorf("%s: expected status 200, got %d", tt.target, w.Code)
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("%s: expected body '%s', got '%s'", tt.target, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestPathModeMethodNotAllowed(t *testing.T) {
+	for _, mode := range []PathMode{PathRedirect, PathLenient} {
+		app := New()
+		app.SetPathMode(mode)
+		app.GET("/users", func(c *Context) { c.Text("users") })
+
+		for _, path := range []string{"/users/", "//users", "/a/../users"} {
+			req := httptest.NewRequest("POST", "/", nil)
+			req.URL.Path = path
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if w.Code != 405 {
+				t.Errorf("mode %d, POST %s: expected status 405, got %d", mode, path, w.Code)
+			}
+			if allow := w.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+				t.Errorf("mode %d, POST %s: expected Allow 'GET, HEAD, OPTIONS', got '%s'", mode, path, allow)
+			}
+		}
+	}
+}
+
+func TestPathModeRedirectMiddleware(t *testing.T) {
+	var status int
+	app := New()
+	app.SetPathMode(PathRedirect)
+	app.Use(func(c *Context) {
+		c.Next()
+		status = c.StatusCode()
+	})
+	app.Use(CORS())
+	app.GET("/users", func(c *Context) { c.Text("users") })
+
+	req := httptest.NewRequest("GET", "/users/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 301 || status != 301 {
+		t.Errorf("Expected the middleware to see a 301, got %d (response %d)", status, w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") == "" {
+		t.Error("Expected CORS headers on the redirect")
+	}
+}
@@ -0,0 +1,104 @@
+package gofsen
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem document d'erreur au format RFC 9457 (application/problem+json).
+// Les membres d'Extensions sont ajoutés au premier niveau du document, sans
+// pouvoir remplacer les membres standards.
+type Problem struct {
+	Type       string // URI identifiant le type de problème, "about:blank" si vide
+	Title      string // résumé du type de problème, le texte du statut par défaut
+	Status     int
+	Detail     string // explication propre à cette occurrence
+	Instance   string // URI de cette occurrence, le chemin de la requête par défaut
+	Extensions map[string]interface{}
+}
+
+// SetProblemDetails rend les erreurs générées par le framework (404, 405,
+// panics, erreurs des handlers, validation, Context.Error) au format
+// application/problem+json plutôt qu'au format JSON historique
+func (r *Router) SetProblemDetails(enabled bool) {
+	r.problemDetails = enabled
+}
+
+// Problem écrit le document avec le Content-Type application/problem+json
+func (c *Context) Problem(problem Problem) {
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+	if problem.Title == "" && (problem.Type == "" || problem.Type == "about:blank") {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	if problem.Instance == "" && c.Request != nil {
+		problem.Instance = c.Request.URL.Path
+	}
+
+	c.ResponseWriter.Header().Set("Content-Type", "application/problem+json")
+	c.Status(problem.Status)
+	json.NewEncoder(c.ResponseWriter).Encode(problem)
+}
+
+// Error permet de retourner un Problem depuis un handler WrapE
+func (p *Problem) Error() string {
+	message := p.Title
+	if message == "" {
+		message = http.StatusText(p.Status)
+	}
+	if p.Detail != "" {
+		message += ": " + p.Detail
+	}
+	return message
+}
+
+// MarshalJSON aplatit les extensions à côté des membres standards
+func (p Problem) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		doc[key] = value
+	}
+
+	standard := map[string]interface{}{
+		"type":     p.Type,
+		"title":    p.Title,
+		"detail":   p.Detail,
+		"instance": p.Instance,
+	}
+	for key, value := range standard {
+		delete(doc, key)
+		if value != "" {
+			doc[key] = value
+		}
+	}
+	delete(doc, "status")
+	if p.Status != 0 {
+		doc["status"] = p.Status
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON lit un document problem+json, les membres inconnus allant dans Extensions
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	*p = Problem{}
+	p.Type, _ = doc["type"].(string)
+	p.Title, _ = doc["title"].(string)
+	p.Detail, _ = doc["detail"].(string)
+	p.Instance, _ = doc["instance"].(string)
+	if status, ok := doc["status"].(float64); ok {
+		p.Status = int(status)
+	}
+	for _, key := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(doc, key)
+	}
+	if len(doc) > 0 {
+		p.Extensions = doc
+	}
+	return nil
+}
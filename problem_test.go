@@ -0,0 +1,142 @@
+package gofsen
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Expected Content-Type 'application/problem+json', got '%s'", ct)
+	}
+	var problem Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Invalid problem document: %v (%s)", err, w.Body.String())
+	}
+	return problem
+}
+
+func TestContextProblem(t *testing.T) {
+	app := New()
+	app.POST("/transfers", func(c *Context) {
+		c.Problem(Problem{
+			Type:   "https://example.com/probs/out-of-credit",
+			Title:  "You do not have enough credit.",
+			Status: 403,
+			Detail: "Your current balance is 30, but that costs 50.",
+			Extensions: map[string]interface{}{
+				"balance": 30,
+				"status":  999,
+			},
+		})
+	})
+
+	req := httptest.NewRequest("POST", "/transfers", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 403 {
+		t.Errorf("Expected status 403, got %d", w.Code)
+	}
+	want := Problem{
+		Type:       "https://example.com/probs/out-of-credit",
+		Title:      "You do not have enough credit.",
+		Status:     403,
+		Detail:     "Your current balance is 30, but that costs 50.",
+		Instance:   "/transfers",
+		Extensions: map[string]interface{}{"balance": float64(30)},
+	}
+	if got := decodeProblem(t, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestProblemDetailsMode(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	app := New()
+	app.SetProblemDetails(true)
+	app.Use(Recovery())
+	app.Use(ValidateOpenAPI(doc))
+	app.POST("/users", func(c *Context) {})
+	app.GET("/panic", func(c *Context) { panic("boom") })
+	app.GET("/orders/:id", WrapE(func(c *Context) error {
+		return NewHTTPError(404, "order_not_found", "Order not found")
+	}))
+	app.GET("/legacy", func(c *Context) { c.Error(409, "Already exists") })
+
+	tests := []struct {
+		method string
+		path   string
+		want   Problem
+	}{
+		{"GET", "/missing", Problem{Title: "Not Found", Status: 404, Detail: "Route not found", Instance: "/missing"}},
+		{"DELETE", "/users", Problem{Title: "Method Not Allowed", Status: 405, Detail: "Method not allowed", Instance: "/users"}},
+		{"GET", "/panic", Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Instance: "/panic"}},
+		{"GET", "/orders/7", Problem{Title: "Not Found", Status: 404, Detail: "Order not found", Instance: "/orders/7", Extensions: map[string]interface{}{"code": "order_not_found"}}},
+		{"GET", "/legacy", Problem{Title: "Conflict", Status: 409, Detail: "Already exists", Instance: "/legacy"}},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.want.Status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.want.Status, w.Code)
+		}
+		if got := decodeProblem(t, w); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s: expected %+v, got %+v", tt.method, tt.path, tt.want, got)
+		}
+	}
+
+	// Erreurs de validation : la liste des violations est une extension
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"A","email":"ada@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	problem := decodeProblem(t, w)
+	if problem.Status != 400 || problem.Detail != "Request validation failed" {
+		t.Errorf("Expected validation problem, got %+v", problem)
+	}
+	errs, _ := problem.Extensions["errors"].([]interface{})
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 violation, got %v", problem.Extensions["errors"])
+	}
+}
+
+func TestReturnProblem(t *testing.T) {
+	app := New()
+	app.GET("/quota", WrapE(func(c *Context) error {
+		return &Problem{Type: "https://example.com/probs/quota", Title: "Quota exceeded", Status: 429}
+	}))
+
+	req := httptest.NewRequest("GET", "/quota", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 429 {
+		t.Errorf("Expected status 429, got %d", w.Code)
+	}
+	if got := decodeProblem(t, w); got.Type != "https://example.com/probs/quota" || got.Title != "Quota exceeded" {
+		t.Errorf("Expected returned problem to be rendered, got %+v", got)
+	}
+}
+
+func TestProblemMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Problem{Status: 400, Detail: "Bad input", Extensions: map[string]interface{}{"title": "ignored", "field": "name"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != `{"detail":"Bad input","field":"name","status":400}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
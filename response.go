@@ -0,0 +1,106 @@
+package gofsen
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// responseWriter enveloppe le http.ResponseWriter de la requête pour suivre
+// le statut, la taille du corps et l'envoi des en-têtes. WriteHeader est
+// différé jusqu'à la première écriture : c.Status(201).JSON(...) peut donc
+// encore positionner Content-Type.
+type responseWriter struct {
+	http.ResponseWriter
+	status   int
+	size     int
+	written  bool
+	hijacked bool
+}
+
+// WriteHeader mémorise le statut ; il n'est envoyé qu'à la première écriture
+// (ou à la fin de la requête). Un statut déjà envoyé ne peut plus changer.
+func (w *responseWriter) WriteHeader(code int) {
+	if w.written {
+		return
+	}
+	w.status = code
+}
+
+// Write envoie les en-têtes s'ils ne l'ont pas encore été, puis le corps
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.writeHeaderNow()
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// writeHeaderNow envoie le statut (200 par défaut) et les en-têtes
+func (w *responseWriter) writeHeaderNow() {
+	if w.written || w.hijacked {
+		return
+	}
+	w.written = true
+	w.ResponseWriter.WriteHeader(w.Status())
+}
+
+// Status retourne le statut envoyé ou à envoyer
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Flush envoie les en-têtes puis les données en attente (streaming, SSE)
+func (w *responseWriter) Flush() {
+	w.writeHeaderNow()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack laisse un handler reprendre la connexion (ex: WebSocket)
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("gofsen: response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap donne accès au ResponseWriter d'origine, ex: pour http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// StatusCode retourne le statut de la réponse : celui déjà envoyé, celui
+// positionné par Status, ou 200 par défaut
+func (c *Context) StatusCode() int {
+	if c.writer == nil {
+		return 0
+	}
+	return c.writer.Status()
+}
+
+// Size retourne le nombre d'octets du corps déjà écrits
+func (c *Context) Size() int {
+	if c.writer == nil {
+		return 0
+	}
+	return c.writer.size
+}
+
+// Written indique si le statut et les en-têtes ont déjà été envoyés au client
+// (ou au writer qui met la réponse en mémoire tampon, ex: validation des réponses)
+func (c *Context) Written() bool {
+	if w, ok := c.ResponseWriter.(interface{ Written() bool }); ok {
+		return w.Written()
+	}
+	return c.writer != nil && c.writer.written
+}
@@ -0,0 +1,129 @@
+package gofsen
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStatusKeepsHeaders(t *testing.T) {
+	app := New()
+	app.POST("/users", func(c *Context) {
+		c.Status(201).JSON(map[string]string{"id": "1"})
+	})
+
+	req := httptest.NewRequest("POST", "/users", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 201 {
+		t.Errorf("Expected status 201, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type 'application/json', got '%s'", ct)
+	}
+}
+
+func TestResponseWriterState(t *testing.T) {
+	app := New()
+
+	var before, after struct {
+		status  int
+		size    int
+		written bool
+	}
+	app.Use(func(c *Context) {
+		c.Next()
+		after.status, after.size, after.written = c.StatusCode(), c.Size(), c.Written()
+	})
+	app.GET("/items", func(c *Context) {
+		c.Status(202)
+		before.status, before.size, before.written = c.StatusCode(), c.Size(), c.Written()
+		c.Text("hello")
+		c.Status(500) // trop tard : les en-têtes sont envoyés
+	})
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if before.status != 202 || before.size != 0 || before.written {
+		t.Errorf("Expected 202/0/false before writing, got %d/%d/%v", before.status, before.size, before.written)
+	}
+	if after.status != 202 || after.size != 5 || !after.written {
+		t.Errorf("Expected 202/5/true after writing, got %d/%d/%v", after.status, after.size, after.written)
+	}
+	if w.Code != 202 {
+		t.Errorf("Expected status 202, got %d", w.Code)
+	}
+}
+
+func TestStatusWithoutBody(t *testing.T) {
+	app := New()
+	app.DELETE("/items/:id", func(c *Context) { c.Status(204) })
+	app.GET("/empty", func(c *Context) {})
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{"DELETE", "/items/1", 204},
+		{"GET", "/empty", 200},
+		{"HEAD", "/empty", 200},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.code, w.Code)
+		}
+	}
+}
+
+func TestResponseWriterFlush(t *testing.T) {
+	app := New()
+	app.GET("/stream", func(c *Context) {
+		c.ResponseWriter.Header().Set("Content-Type", "text/event-stream")
+		c.ResponseWriter.(http.Flusher).Flush()
+		if !c.Written() {
+			t.Error("Expected headers to be written after Flush")
+		}
+		c.Text("data: hello\n\n")
+	})
+
+	req := httptest.NewRequest("GET", "/stream", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if !w.Flushed {
+		t.Error("Expected response to be flushed")
+	}
+	if w.Body.String() != "data: hello\n\n" {
+		t.Errorf("Expected streamed body, got '%s'", w.Body.String())
+	}
+}
+
+func TestLoggerReportsStatus(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	app.Use(Logger())
+	app.GET("/missing", func(c *Context) { c.Status(404).Text("nope") })
+
+	req := httptest.NewRequest("GET", "/missing", nil)
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(buf.String(), "GET /missing 404 4B - ") {
+		t.Errorf("Expected status and size in log, got '%s'", buf.String())
+	}
+}
@@ -0,0 +1,99 @@
+package gofsen
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Describe ajoute un résumé lisible à la route
+func (route *Route) Describe(summary string) *Route {
+	route.Summary = summary
+	return route
+}
+
+// Tag ajoute des tags à la route, ex: pour regrouper la documentation
+func (route *Route) Tag(tags ...string) *Route {
+	route.Tags = append(route.Tags, tags...)
+	return route
+}
+
+// Deprecate marque la route comme dépréciée
+func (route *Route) Deprecate() *Route {
+	route.Deprecated = true
+	return route
+}
+
+// Annotate associe une métadonnée libre à la route, ex: Annotate("auth", "admin")
+func (route *Route) Annotate(key string, value interface{}) *Route {
+	if route.Metadata == nil {
+		route.Metadata = make(map[string]interface{})
+	}
+	route.Metadata[key] = value
+	return route
+}
+
+// routeInfo est la représentation JSON d'une route exposée par RoutesHandler
+type routeInfo struct {
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	Host        string                 `json:"host,omitempty"`
+	Version     string                 `json:"version,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Params      []string               `json:"params,omitempty"`
+	Handler     string                 `json:"handler"`
+	Middlewares []string               `json:"middlewares"`
+}
+
+// RoutesHandler retourne un handler qui liste la table de routage en JSON,
+// middlewares globaux et de groupe compris. À monter explicitement, ex:
+// app.GET("/_routes", app.RoutesHandler())
+func (r *Router) RoutesHandler() HandlerFunc {
+	return func(c *Context) {
+		routes := r.Routes()
+		infos := make([]routeInfo, 0, len(routes))
+		for _, route := range routes {
+			middlewares := make([]string, 0, len(r.middlewares))
+			for _, middleware := range r.middlewares {
+				middlewares = append(middlewares, funcName(middleware))
+			}
+			for _, middleware := range route.Middlewares() {
+				middlewares = append(middlewares, funcName(middleware))
+			}
+
+			infos = append(infos, routeInfo{
+				Method:      route.Method,
+				Path:        route.Path,
+				Host:        route.Host,
+				Version:     route.Version,
+				Name:        route.Name,
+				Summary:     route.Summary,
+				Tags:        route.Tags,
+				Deprecated:  route.Deprecated,
+				Metadata:    route.Metadata,
+				Params:      route.Params,
+				Handler:     funcName(route.Handler),
+				Middlewares: middlewares,
+			})
+		}
+		c.JSON(infos)
+	}
+}
+
+// funcName retourne le nom d'une fonction sans le chemin de son module,
+// ex: "gofsen.Logger.func1" pour le middleware retourné par Logger()
+func funcName(fn interface{}) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	name := f.Name()
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
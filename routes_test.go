@@ -0,0 +1,70 @@
+package gofsen
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouteMetadata(t *testing.T) {
+	app := New()
+	app.GET("/users/:id", func(c *Context) {}).
+		Named("user").
+		Describe("Get a user").
+		Tag("users", "public").
+		Deprecate().
+		Annotate("auth", "admin")
+
+	routes := app.Routes()
+	if len(routes) != 1 {
+		t.Fatalf("Expected 1 route, got %d", len(routes))
+	}
+
+	route := routes[0]
+	if route.Name != "user" || route.Summary != "Get a user" || !route.Deprecated {
+		t.Errorf("Unexpected metadata: %+v", route)
+	}
+	if strings.Join(route.Tags, ",") != "users,public" {
+		t.Errorf("Expected tags 'users,public', got %v", route.Tags)
+	}
+	if route.Metadata["auth"] != "admin" {
+		t.Errorf("Expected metadata auth=admin, got %v", route.Metadata["auth"])
+	}
+}
+
+func TestRoutesHandler(t *testing.T) {
+	app := New()
+	app.Use(Logger())
+
+	admin := app.Group("/admin")
+	admin.Use(Recovery())
+	admin.GET("/stats", func(c *Context) {}).Describe("Statistics").Tag("admin")
+
+	app.GET("/_routes", app.RoutesHandler())
+
+	req := httptest.NewRequest("GET", "/_routes", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	var infos []routeInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &infos); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(infos))
+	}
+
+	stats := infos[1]
+	if stats.Path != "/admin/stats" || stats.Summary != "Statistics" {
+		t.Errorf("Unexpected route info: %+v", stats)
+	}
+	if len(stats.Middlewares) != 2 ||
+		!strings.HasPrefix(stats.Middlewares[0], "gofsen.Logger") ||
+		!strings.HasPrefix(stats.Middlewares[1], "gofsen.Recovery") {
+		t.Errorf("Expected Logger and Recovery middlewares, got %v", stats.Middlewares)
+	}
+	if len(infos[0].Middlewares) != 1 {
+		t.Errorf("Expected only the global middleware on /_routes, got %v", infos[0].Middlewares)
+	}
+}
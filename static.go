@@ -0,0 +1,312 @@
+package gofsen
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// StaticConfig configuration pour le service de fichiers statiques
+type StaticConfig struct {
+	Browse       bool     // afficher le contenu des répertoires sans fichier index
+	Index        []string // fichiers index d'un répertoire, ["index.html"] par défaut
+	Compressed   bool     // servir les variantes précompressées .br / .gz si le client les accepte
+	CacheControl string   // valeur de l'en-tête Cache-Control, ex: "public, max-age=3600"
+
+	// SPA renvoie le fichier index racine pour les requêtes GET qui acceptent
+	// text/html et ne correspondent à aucun fichier (liens profonds d'une
+	// application monopage). Les chemins ayant une extension et ceux sous
+	// SPAExclude (["/api"] par défaut) gardent un vrai 404. Les fichiers ne sont
+	// alors servis qu'en l'absence de route : ils ne provoquent ni 405 ni
+	// réponse OPTIONS pour les autres méthodes.
+	SPA        bool
+	SPAExclude []string
+}
+
+// staticFallback fichiers servis en mode SPA, consultés au stade du 404
+type staticFallback struct {
+	prefix  string
+	handler HandlerFunc
+}
+
+// precompressed associe les encodages supportés à l'extension de leurs fichiers, par préférence
+var precompressed = []struct {
+	encoding  string
+	extension string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// Static sert les fichiers du répertoire dir sous prefix
+func (r *Router) Static(prefix, dir string) {
+	r.StaticFS(prefix, os.DirFS(dir))
+}
+
+// StaticFS sert les fichiers d'un fs.FS (ex: embed.FS) sous prefix
+func (r *Router) StaticFS(prefix string, fsys fs.FS) {
+	r.StaticWithConfig(prefix, fsys, StaticConfig{})
+}
+
+// StaticWithConfig sert les fichiers d'un fs.FS sous prefix avec une configuration
+// personnalisée. Ces routes sont exclues du document OpenAPI.
+func (r *Router) StaticWithConfig(prefix string, fsys fs.FS, config StaticConfig) {
+	if len(config.Index) == 0 {
+		config.Index = []string{"index.html"}
+	}
+	if config.SPA && config.SPAExclude == nil {
+		config.SPAExclude = []string{"/api"}
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	handler := staticHandler(fsys, config)
+	if config.SPA {
+		// Un joker GET /*filepath monté à la racine répondrait 405 à POST /api/nope
+		r.fallbacks = append(r.fallbacks, staticFallback{prefix: prefix, handler: handler})
+		return
+	}
+	if prefix != "" {
+		r.GET(prefix, handler).hidden = true
+	}
+	r.GET(prefix+"/*filepath", handler).hidden = true
+}
+
+// fallbackRoute retourne la route des fichiers SPA dont le préfixe (le plus
+// long) couvre le chemin d'une requête GET ou HEAD sans route, avec son paramètre filepath
+func (r *Router) fallbackRoute(method, p string, raw bool) (*Route, map[string]string) {
+	if method != "GET" && method != "HEAD" {
+		return nil, nil
+	}
+
+	var match *staticFallback
+	for i, fallback := range r.fallbacks {
+		if p != fallback.prefix && !strings.HasPrefix(p, fallback.prefix+"/") {
+			continue
+		}
+		if match == nil || len(fallback.prefix) > len(match.prefix) {
+			match = &r.fallbacks[i]
+		}
+	}
+	if match == nil {
+		return nil, nil
+	}
+
+	filepath := strings.TrimPrefix(p[len(match.prefix):], "/")
+	if raw {
+		if unescaped, err := url.PathUnescape(filepath); err == nil {
+			filepath = unescaped
+		}
+	}
+	route := &Route{Method: method, Path: match.prefix + "/*filepath", Handler: match.handler}
+	return route, map[string]string{"filepath": filepath}
+}
+
+// staticHandler sert le fichier désigné par le paramètre filepath
+func staticHandler(fsys fs.FS, config StaticConfig) HandlerFunc {
+	return func(c *Context) {
+		name, ok := staticName(c.Param("filepath"))
+		if !ok {
+			c.router.notFoundHandler()(c)
+			return
+		}
+
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			if config.SPA && isSPARequest(c.Request, config.SPAExclude) {
+				serveSPAIndex(c, fsys, config)
+				return
+			}
+			c.router.notFoundHandler()(c)
+			return
+		}
+
+		if info.IsDir() {
+			// Les liens relatifs d'un répertoire supposent un slash final. Les slashs
+			// initiaux sont fusionnés : //assets/ serait une URL vers l'hôte "assets".
+			if !strings.HasSuffix(c.Request.URL.Path, "/") {
+				location := "/" + strings.TrimLeft(c.Request.URL.Path, "/") + "/"
+				redirectPath(c.ResponseWriter, c.Request, location, false)
+				return
+			}
+			if index, ok := findIndex(fsys, name, config.Index); ok {
+				name = index
+			} else if config.Browse {
+				listDirectory(c, fsys, name)
+				return
+			} else {
+				c.router.notFoundHandler()(c)
+				return
+			}
+		}
+
+		if config.CacheControl != "" {
+			c.ResponseWriter.Header().Set("Cache-Control", config.CacheControl)
+		}
+		if err := serveFile(c, fsys, name, config.Compressed); err != nil {
+			c.router.notFoundHandler()(c)
+		}
+	}
+}
+
+// isSPARequest indique si une requête sans fichier correspondant doit recevoir
+// le fichier index de l'application monopage plutôt qu'un 404
+func isSPARequest(req *http.Request, exclude []string) bool {
+	if req.Method != "GET" && req.Method != "HEAD" {
+		return false
+	}
+	if !strings.Contains(req.Header.Get("Accept"), "text/html") {
+		return false
+	}
+
+	p := req.URL.Path
+	for _, prefix := range exclude {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return false
+		}
+	}
+
+	// Un fichier manquant (ex: /assets/app.js) n'est pas un lien profond
+	return path.Ext(path.Base(p)) == ""
+}
+
+// serveSPAIndex sert le fichier index racine, sans mise en cache
+func serveSPAIndex(c *Context, fsys fs.FS, config StaticConfig) {
+	index, ok := findIndex(fsys, ".", config.Index)
+	if !ok {
+		c.router.notFoundHandler()(c)
+		return
+	}
+
+	c.ResponseWriter.Header().Set("Cache-Control", "no-cache")
+	if err := serveFile(c, fsys, index, config.Compressed); err != nil {
+		c.router.notFoundHandler()(c)
+	}
+}
+
+// staticName convertit le chemin demandé en nom valide pour fs.FS.
+// Les segments ".." sont refusés plutôt que nettoyés pour bloquer toute traversée.
+func staticName(filepath string) (string, bool) {
+	if strings.Contains(filepath, "\x00") || strings.Contains(filepath, "\\") {
+		return "", false
+	}
+	for _, segment := range strings.Split(filepath, "/") {
+		if segment == ".." {
+			return "", false
+		}
+	}
+
+	name := strings.Trim(path.Clean("/"+filepath), "/")
+	if name == "" {
+		name = "."
+	}
+	return name, fs.ValidPath(name)
+}
+
+// findIndex retourne le premier fichier index présent dans le répertoire dir
+func findIndex(fsys fs.FS, dir string, indexes []string) (string, bool) {
+	for _, index := range indexes {
+		name := path.Join(dir, index)
+		if info, err := fs.Stat(fsys, name); err == nil && !info.IsDir() {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// serveFile envoie le fichier (ou sa variante précompressée) avec http.ServeContent,
+// qui gère Range, If-Modified-Since et les requêtes HEAD
+func serveFile(c *Context, fsys fs.FS, name string, compressed bool) error {
+	header := c.ResponseWriter.Header()
+	served := name
+
+	if compressed {
+		header.Add("Vary", "Accept-Encoding")
+		accepted := c.Request.Header.Get("Accept-Encoding")
+		for _, variant := range precompressed {
+			if !acceptsEncoding(accepted, variant.encoding) {
+				continue
+			}
+			if info, err := fs.Stat(fsys, name+variant.extension); err == nil && !info.IsDir() {
+				served = name + variant.extension
+				header.Set("Content-Encoding", variant.encoding)
+				if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+					header.Set("Content-Type", ctype)
+				}
+				break
+			}
+		}
+	}
+
+	f, err := fsys.Open(served)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(data)
+	}
+
+	http.ServeContent(c.ResponseWriter, c.Request, path.Base(name), info.ModTime(), content)
+	return nil
+}
+
+// acceptsEncoding indique si l'en-tête Accept-Encoding accepte l'encodage (q > 0)
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(fields[0]), encoding) {
+			continue
+		}
+		for _, param := range fields[1:] {
+			param = strings.ReplaceAll(param, " ", "")
+			if param == "q=0" || strings.HasPrefix(param, "q=0.") && strings.Trim(param[4:], "0") == "" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// listDirectory affiche le contenu d'un répertoire en HTML
+func listDirectory(c *Context, fsys fs.FS, dir string) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		c.router.notFoundHandler()(c)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("<!doctype html>\n<meta name=\"viewport\" content=\"width=device-width\">\n<pre>\n")
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		link := url.URL{Path: name}
+		fmt.Fprintf(&b, "<a href=\"%s\">%s</a>\n", html.EscapeString(link.String()), html.EscapeString(name))
+	}
+	b.WriteString("</pre>\n")
+
+	c.HTML(b.String())
+}
@@ -0,0 +1,215 @@
+package gofsen
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func newStaticFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":         {Data: []byte("<h1>home</h1>")},
+		"css/app.css":        {Data: []byte("body{}")},
+		"js/app.js":          {Data: []byte("console.log(1)")},
+		"js/app.js.gz":       {Data: []byte("gzip-bytes")},
+		"js/app.js.br":       {Data: []byte("brotli-bytes")},
+		"docs/guide.txt":     {Data: []byte("guide")},
+		"docs/sub/notes.txt": {Data: []byte("notes")},
+	}
+}
+
+func serveStatic(app *Router, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", target, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+func TestStaticFS(t *testing.T) {
+	app := New()
+	app.StaticWithConfig("/assets", newStaticFS(), StaticConfig{CacheControl: "public, max-age=60"})
+
+	w := serveStatic(app, "/assets/css/app.css", nil)
+	if w.Code != 200 || w.Body.String() != "body{}" {
+		t.Errorf("Expected app.css content, got %d '%s'", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/css") {
+		t.Errorf("Expected text/css, got '%s'", ct)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "public, max-age=60" {
+		t.Errorf("Expected Cache-Control 'public, max-age=60', got '%s'", cc)
+	}
+
+	w = serveStatic(app, "/assets/", nil)
+	if w.Body.String() != "<h1>home</h1>" {
+		t.Errorf("Expected index.html content, got '%s'", w.Body.String())
+	}
+
+	w = serveStatic(app, "/assets", nil)
+	if w.Code != 301 || w.Header().Get("Location") != "/assets/" {
+		t.Errorf("Expected redirect to /assets/, got %d '%s'", w.Code, w.Header().Get("Location"))
+	}
+
+	w = serveStatic(app, "/assets/missing.css", nil)
+	if w.Code != 404 {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+
+	// Sans listing, un répertoire sans index est un 404
+	w = serveStatic(app, "/assets/docs/", nil)
+	if w.Code != 404 {
+		t.Errorf("Expected status 404 for a directory without index, got %d", w.Code)
+	}
+}
+
+func TestStaticDirectoryRedirect(t *testing.T) {
+	for _, config := range []StaticConfig{{}, {SPA: true}} {
+		app := New()
+		app.StaticWithConfig("/", newStaticFS(), config)
+
+		for _, target := range []string{"/css", "//css", "///css"} {
+			w := serveStatic(app, target, nil)
+			if w.Code != 301 || w.Header().Get("Location") != "/css/" {
+				t.Errorf("SPA=%v, %s: expected redirect to /css/, got %d '%s'", config.SPA, target, w.Code, w.Header().Get("Location"))
+			}
+		}
+	}
+}
+
+func TestStaticBrowse(t *testing.T) {
+	app := New()
+	app.StaticWithConfig("/files", newStaticFS(), StaticConfig{Browse: true})
+
+	w := serveStatic(app, "/files/docs/", nil)
+	if w.Code != 200 {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `<a href="guide.txt">guide.txt</a>`) || !strings.Contains(body, `<a href="sub/">sub/</a>`) {
+		t.Errorf("Expected a directory listing, got '%s'", body)
+	}
+}
+
+func TestStaticPrecompressed(t *testing.T) {
+	app := New()
+	app.StaticWithConfig("/static", newStaticFS(), StaticConfig{Compressed: true})
+
+	tests := []struct {
+		accept   string
+		body     string
+		encoding string
+	}{
+		{"gzip, deflate, br", "brotli-bytes", "br"},
+		{"gzip", "gzip-bytes", "gzip"},
+		{"br;q=0, gzip", "gzip-bytes", "gzip"},
+		{"", "console.log(1)", ""},
+	}
+
+	for _, tt := range tests {
+		w := serveStatic(app, "/static/js/app.js", map[string]string{"Accept-Encoding": tt.accept})
+		if w.Body.String() != tt.body {
+			t.Errorf("Accept-Encoding %q: expected body '%s', got '%s'", tt.accept, tt.body, w.Body.String())
+		}
+		if enc := w.Header().Get("Content-Encoding"); enc != tt.encoding {
+			t.Errorf("Accept-Encoding %q: expected Content-Encoding '%s', got '%s'", tt.accept, tt.encoding, enc)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, "javascript") {
+			t.Errorf("Accept-Encoding %q: expected a javascript Content-Type, got '%s'", tt.accept, ct)
+		}
+	}
+}
+
+func TestStaticPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	public := filepath.Join(dir, "public")
+	os.Mkdir(public, 0o755)
+	os.WriteFile(filepath.Join(public, "ok.txt"), []byte("ok"), 0o644)
+	os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o644)
+
+	app := New()
+	app.Static("/public", public)
+
+	w := serveStatic(app, "/public/ok.txt", nil)
+	if w.Body.String() != "ok" {
+		t.Errorf("Expected body 'ok', got '%s'", w.Body.String())
+	}
+
+	for _, target := range []string{
+		"/public/../secret.txt",
+		"/public/%2e%2e/secret.txt",
+		"/public/..%2fsecret.txt",
+		"/public/..%5csecret.txt",
+	} {
+		w := serveStatic(app, target, nil)
+		if strings.Contains(w.Body.String(), "secret") {
+			t.Errorf("%s: path traversal served the secret file", target)
+		}
+	}
+}
+
+func TestStaticSPAFallback(t *testing.T) {
+	app := New()
+	app.GET("/api/users", func(c *Context) { c.JSON([]string{"alice"}) })
+	app.StaticWithConfig("/", newStaticFS(), StaticConfig{SPA: true})
+
+	browser := map[string]string{"Accept": "text/html,application/xhtml+xml,*/*;q=0.8"}
+
+	tests := []struct {
+		target  string
+		headers map[string]string
+		code    int
+		body    string
+	}{
+		{"/dashboard/settings", browser, 200, "<h1>home</h1>"},
+		{"/css/app.css", browser, 200, "body{}"},
+		{"/api/users", browser, 200, "[\"alice\"]\n"},
+		{"/api/unknown", browser, 404, ""},
+		{"/assets/missing.js", browser, 404, ""},
+		{"/dashboard/settings", map[string]string{"Accept": "application/json"}, 404, ""},
+	}
+
+	for _, tt := range tests {
+		w := serveStatic(app, tt.target, tt.headers)
+		if w.Code != tt.code {
+			t.Errorf("%s: expected status %d, got %d", tt.target, tt.code, w.Code)
+			continue
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: expected body '%s', got '%s'", tt.target, tt.body, w.Body.String())
+		}
+	}
+
+	// Le mode SPA ne répond qu'à GET et HEAD : les autres méthodes gardent 404 ou 405
+	methods := []struct {
+		method string
+		target string
+		code   int
+		allow  string
+	}{
+		{"POST", "/api/nope", 404, ""},
+		{"DELETE", "/dashboard/settings", 404, ""},
+		{"OPTIONS", "/dashboard/settings", 404, ""},
+		{"POST", "/api/users", 405, "GET, HEAD, OPTIONS"},
+		{"HEAD", "/dashboard/settings", 200, ""},
+	}
+
+	for _, tt := range methods {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		req.Header.Set("Accept", "text/html")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.target, tt.code, w.Code)
+		}
+		if allow := w.Header().Get("Allow"); allow != tt.allow {
+			t.Errorf("%s %s: expected Allow '%s', got '%s'", tt.method, tt.target, tt.allow, allow)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package gofsen
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ContextKey type des clés du magasin de la requête lues via context.Context,
+// ex: ctx.Value(gofsen.ContextKey("user"))
+type ContextKey string
+
+// contextStore valeurs propres à une requête, partagées entre Context et
+// le context.Context de la requête
+type contextStore struct {
+	mu     sync.RWMutex
+	values map[string]interface{}
+}
+
+// storeContext expose le magasin de la requête aux bibliothèques qui ne
+// reçoivent qu'un context.Context
+type storeContext struct {
+	context.Context
+	store *contextStore
+}
+
+// Value cherche les clés ContextKey dans le magasin, toute autre clé dans le contexte parent
+func (ctx storeContext) Value(key interface{}) interface{} {
+	if name, ok := key.(ContextKey); ok {
+		if value, ok := ctx.store.get(string(name)); ok {
+			return value
+		}
+	}
+	return ctx.Context.Value(key)
+}
+
+// attachStore crée le magasin de la requête et l'expose via son context.Context.
+// Appelé une fois à la création du Context : les copies de la requête faites
+// ensuite (WithContext, WrapMiddleware...) héritent du magasin.
+func (c *Context) attachStore() {
+	c.store = &contextStore{}
+	if c.Request != nil {
+		c.Request = c.Request.WithContext(storeContext{Context: c.Request.Context(), store: c.store})
+	}
+}
+
+func (s *contextStore) get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set associe une valeur à la clé pour la durée de la requête. La valeur est
+// aussi visible via c.Request.Context().Value(gofsen.ContextKey(key)).
+func (c *Context) Set(key string, value interface{}) {
+	if c.store == nil {
+		// Context construit hors de ServeHTTP
+		c.attachStore()
+	}
+	c.store.mu.Lock()
+	if c.store.values == nil {
+		c.store.values = make(map[string]interface{})
+	}
+	c.store.values[key] = value
+	c.store.mu.Unlock()
+}
+
+// Get retourne la valeur associée à la clé et indique si elle existe
+func (c *Context) Get(key string) (interface{}, bool) {
+	if c.store == nil {
+		return nil, false
+	}
+	return c.store.get(key)
+}
+
+// MustGet retourne la valeur associée à la clé, ou panic si elle n'existe pas
+func (c *Context) MustGet(key string) interface{} {
+	value, ok := c.Get(key)
+	if !ok {
+		panic(fmt.Sprintf("gofsen: key %q does not exist", key))
+	}
+	return value
+}
+
+// Get retourne la valeur typée associée à la clé ; ok vaut false si elle
+// n'existe pas ou n'est pas du type T. Ex: user, ok := gofsen.Get[*User](c, "user")
+func Get[T any](c *Context, key string) (T, bool) {
+	value, _ := c.Get(key)
+	typed, ok := value.(T)
+	return typed, ok
+}
+
+// MustGet retourne la valeur typée associée à la clé, ou panic si elle
+// n'existe pas ou n'est pas du type T
+func MustGet[T any](c *Context, key string) T {
+	value := c.MustGet(key)
+	typed, ok := value.(T)
+	if !ok {
+		panic(fmt.Sprintf("gofsen: key %q holds %T, not %s", key, value, reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	return typed
+}
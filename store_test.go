@@ -0,0 +1,145 @@
+package gofsen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type storeUser struct {
+	Name string
+}
+
+// userFromContext simule une bibliothèque qui ne reçoit qu'un context.Context
+func userFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(ContextKey("user")).(*storeUser)
+	if user == nil {
+		return ""
+	}
+	return user.Name
+}
+
+func TestContextStore(t *testing.T) {
+	app := New()
+	app.Use(func(c *Context) {
+		c.Set("user", &storeUser{Name: "ada"})
+		c.Set("role", "admin")
+		c.Next()
+	})
+	app.GET("/me", func(c *Context) {
+		user, ok := Get[*storeUser](c, "user")
+		if !ok {
+			t.Error("Expected typed user")
+		}
+		if _, ok := Get[int](c, "role"); ok {
+			t.Error("Expected wrong type to report ok=false")
+		}
+		if _, ok := Get[string](c, "missing"); ok {
+			t.Error("Expected missing key to report ok=false")
+		}
+		if value, ok := c.Get("role"); !ok || value != "admin" {
+			t.Errorf("Expected role 'admin', got %v", value)
+		}
+
+		// Valeur ajoutée après la création du context.Context : visible aussi
+		c.Set("request_id", "42")
+		ctx := c.Request.Context()
+		c.Text(user.Name + " " + userFromContext(ctx) + " " + ctx.Value(ContextKey("request_id")).(string))
+	})
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Body.String() != "ada ada 42" {
+		t.Errorf("Expected body 'ada ada 42', got '%s'", w.Body.String())
+	}
+}
+
+func TestContextStoreParentValues(t *testing.T) {
+	type traceKey struct{}
+
+	app := New()
+	app.GET("/", func(c *Context) {
+		c.Set("user", &storeUser{Name: "ada"})
+		ctx := c.Request.Context()
+		if ctx.Value("user") != nil {
+			t.Error("Expected plain string key to bypass the store")
+		}
+		trace, _ := ctx.Value(traceKey{}).(string)
+		c.Text(trace + " " + userFromContext(context.WithValue(ctx, traceKey{}, "child")))
+	})
+
+	parent := context.WithValue(context.Background(), traceKey{}, "trace-1")
+	req := httptest.NewRequest("GET", "/", nil).WithContext(parent)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Body.String() != "trace-1 ada" {
+		t.Errorf("Expected body 'trace-1 ada', got '%s'", w.Body.String())
+	}
+}
+
+func TestMustGet(t *testing.T) {
+	c := &Context{Request: httptest.NewRequest("GET", "/", nil)}
+	c.Set("count", 3)
+
+	if MustGet[int](c, "count") != 3 || c.MustGet("count") != 3 {
+		t.Error("Expected MustGet to return 3")
+	}
+
+	for _, tt := range []struct {
+		name string
+		call func()
+		want string
+	}{
+		{"missing", func() { c.MustGet("missing") }, `key "missing" does not exist`},
+		{"generic missing", func() { MustGet[int](c, "missing") }, `key "missing" does not exist`},
+		{"wrong type", func() { MustGet[error](c, "count") }, `key "count" holds int, not error`},
+	} {
+		func() {
+			defer func() {
+				r := recover()
+				if r == nil || !strings.Contains(r.(string), tt.want) {
+					t.Errorf("%s: expected panic containing '%s', got %v", tt.name, tt.want, r)
+				}
+			}()
+			tt.call()
+		}()
+	}
+}
+
+func TestContextStoreAfterWrapMiddleware(t *testing.T) {
+	type traceKey struct{}
+
+	var after string
+	app := New()
+	app.Use(func(c *Context) {
+		c.Next()
+		// Requête restaurée par WrapMiddleware : le magasin reste visible
+		after = userFromContext(c.Request.Context())
+	})
+	app.Use(WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), traceKey{}, "trace-1")))
+		})
+	}))
+	app.GET("/", func(c *Context) {
+		c.Set("user", &storeUser{Name: "ada"})
+		trace, _ := c.Request.Context().Value(traceKey{}).(string)
+		c.Text(trace + " " + userFromContext(c.Request.Context()))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Body.String() != "trace-1 ada" {
+		t.Errorf("Expected body 'trace-1 ada', got '%s'", w.Body.String())
+	}
+	if after != "ada" {
+		t.Errorf("Expected user 'ada' after WrapMiddleware, got '%s'", after)
+	}
+}
@@ -0,0 +1,450 @@
+package gofsen
+
+import (
+	"regexp"
+	"strings"
+)
+
+// nodeKind distingue les différents types de nœuds de l'arbre de routage
+type nodeKind uint8
+
+const (
+	staticKind nodeKind = iota // segment littéral
+	paramKind                  // :param, capture un segment
+	anyKind                    // *wildcard, capture le reste du chemin
+)
+
+// node est un nœud de l'arbre radix compressé (un arbre par méthode HTTP).
+// Les nœuds statiques portent un préfixe partagé par tous leurs descendants,
+// les nœuds param et wildcard n'ont pas de préfixe et capturent une valeur.
+type node struct {
+	kind          nodeKind
+	prefix        string
+	indices       string // premier octet de chaque enfant statique, dans le même ordre que children
+	children      []*node
+	paramChildren []*node // paramètres contraints d'abord, le paramètre libre en dernier
+	anyChild      *node
+	route         *Route
+	constraint    string            // contrainte d'un nœud param, ex: "int" ou "[a-z-]+"
+	check         func(string) bool // validation de la valeur capturée, nil si libre
+}
+
+// insert enregistre la route pour path, relatif au nœud courant. Une route déjà
+// présente pour le même motif est un conflit.
+func (n *node) insert(path string, route *Route) error {
+	if path == "" {
+		if n.route != nil {
+			return newConflictError(route, n.route, false)
+		}
+		n.route = route
+		return nil
+	}
+
+	switch path[0] {
+	case ':':
+		_, constraint, end := paramToken(path)
+		return n.paramNode(constraint).insert(path[end:], route)
+	case '*':
+		if n.anyChild == nil {
+			n.anyChild = &node{kind: anyKind}
+		}
+		return n.anyChild.insert("", route)
+	}
+
+	end := strings.IndexAny(path, ":*")
+	if end < 0 {
+		end = len(path)
+	}
+	return n.insertStatic(path[:end], path[end:], route)
+}
+
+// overlapping retourne une route de l'arbre dont le motif répond à au moins une
+// requête en commun avec path, ou nil s'il n'y en a pas. Les routes de même
+// motif sont ignorées : insert les signale comme doublons.
+func (n *node) overlapping(path string) *Route {
+	segments := splitPattern(path)
+	var found *Route
+	n.walk(func(route *Route) bool {
+		existing := splitPattern(route.Path)
+		if !samePattern(segments, existing) && patternsOverlap(segments, existing) {
+			found = route
+		}
+		return found == nil
+	})
+	return found
+}
+
+// walk appelle fn pour chaque route du sous-arbre de n, tant que fn retourne true
+func (n *node) walk(fn func(*Route) bool) bool {
+	if n.route != nil && !fn(n.route) {
+		return false
+	}
+	for _, child := range n.children {
+		if !child.walk(fn) {
+			return false
+		}
+	}
+	for _, child := range n.paramChildren {
+		if !child.walk(fn) {
+			return false
+		}
+	}
+	if n.anyChild != nil {
+		return n.anyChild.walk(fn)
+	}
+	return true
+}
+
+// paramNode retourne l'enfant paramètre portant cette contrainte, en le créant si besoin
+func (n *node) paramNode(constraint string) *node {
+	for _, child := range n.paramChildren {
+		if child.constraint == constraint {
+			return child
+		}
+	}
+
+	child := &node{kind: paramKind, constraint: constraint, check: compileConstraint(constraint)}
+	if constraint == "" {
+		n.paramChildren = append(n.paramChildren, child)
+		return child
+	}
+
+	// Les paramètres contraints sont essayés avant le paramètre libre
+	i := len(n.paramChildren)
+	if i > 0 && n.paramChildren[i-1].constraint == "" {
+		i--
+	}
+	n.paramChildren = append(n.paramChildren, nil)
+	copy(n.paramChildren[i+1:], n.paramChildren[i:])
+	n.paramChildren[i] = child
+	return child
+}
+
+// insertStatic ajoute le segment littéral static parmi les enfants de n,
+// en découpant un enfant existant si nécessaire, puis poursuit avec rest
+func (n *node) insertStatic(static, rest string, route *Route) error {
+	for i := 0; i < len(n.indices); i++ {
+		if n.indices[i] != static[0] {
+			continue
+		}
+
+		child := n.children[i]
+		l := longestCommonPrefix(child.prefix, static)
+		if l < len(child.prefix) {
+			child.split(l)
+		}
+		if l < len(static) {
+			return child.insertStatic(static[l:], rest, route)
+		}
+		return child.insert(rest, route)
+	}
+
+	child := &node{kind: staticKind, prefix: static}
+	n.indices += static[:1]
+	n.children = append(n.children, child)
+	return child.insert(rest, route)
+}
+
+// split coupe le préfixe du nœud à la position l et déplace le reste dans un nouvel enfant
+func (n *node) split(l int) {
+	child := &node{
+		kind:          staticKind,
+		prefix:        n.prefix[l:],
+		indices:       n.indices,
+		children:      n.children,
+		paramChildren: n.paramChildren,
+		anyChild:      n.anyChild,
+		route:         n.route,
+	}
+
+	n.prefix = n.prefix[:l]
+	n.indices = child.prefix[:1]
+	n.children = []*node{child}
+	n.paramChildren = nil
+	n.anyChild = nil
+	n.route = nil
+}
+
+// match recherche path (déjà débarrassé du préfixe de n) dans le sous-arbre.
+// Les valeurs capturées sont ajoutées à values ; l'ordre de priorité est
+// statique, puis paramètre (contraint avant libre), puis wildcard, avec retour arrière si besoin.
+func (n *node) match(path string, values []string) (*Route, []string) {
+	if path == "" {
+		if n.route != nil {
+			return n.route, values
+		}
+		if n.anyChild != nil {
+			return n.anyChild.route, append(values, "")
+		}
+		return nil, values
+	}
+
+	for i := 0; i < len(n.indices); i++ {
+		if n.indices[i] != path[0] {
+			continue
+		}
+		child := n.children[i]
+		if strings.HasPrefix(path, child.prefix) {
+			if route, v := child.match(path[len(child.prefix):], values); route != nil {
+				return route, v
+			}
+		}
+		break
+	}
+
+	if len(n.paramChildren) > 0 {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end > 0 {
+			value := path[:end]
+			for _, child := range n.paramChildren {
+				if child.check != nil && !child.check(value) {
+					continue
+				}
+				if route, v := child.match(path[end:], append(values, value)); route != nil {
+					return route, v
+				}
+			}
+		}
+	}
+
+	if n.anyChild != nil {
+		return n.anyChild.route, append(values, path)
+	}
+
+	return nil, values
+}
+
+// longestCommonPrefix retourne la longueur du plus long préfixe commun à a et b
+func longestCommonPrefix(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+	i := 0
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// paramToken analyse le paramètre au début de path (":name" ou ":name<contrainte>")
+// et retourne son nom, sa contrainte et la position de fin du segment
+func paramToken(path string) (name, constraint string, end int) {
+	end = 1
+	for end < len(path) && path[end] != '/' && path[end] != '<' {
+		end++
+	}
+	name = path[1:end]
+	if end == len(path) || path[end] != '<' {
+		return name, "", end
+	}
+
+	// La contrainte peut elle-même contenir des chevrons, ex: (?P<x>...)
+	depth := 0
+	for i := end; i < len(path); i++ {
+		switch path[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				return name, path[end+1 : i], i + 1
+			}
+		}
+	}
+	panic("gofsen: unterminated parameter constraint in '" + path + "'")
+}
+
+// compileConstraint convertit une contrainte de paramètre en fonction de validation.
+// Les contraintes "int" et "uuid" sont prédéfinies, toute autre valeur est une regex.
+func compileConstraint(constraint string) func(string) bool {
+	switch constraint {
+	case "":
+		return nil
+	case "int":
+		return isInt
+	case "uuid":
+		return func(value string) bool {
+			_, err := ParseUUID(value)
+			return err == nil
+		}
+	}
+
+	pattern, err := regexp.Compile("^(?:" + constraint + ")$")
+	if err != nil {
+		panic("gofsen: invalid parameter constraint <" + constraint + ">: " + err.Error())
+	}
+	return pattern.MatchString
+}
+
+// isInt indique si value est un entier décimal, éventuellement signé
+func isInt(value string) bool {
+	if value != "" && (value[0] == '-' || value[0] == '+') {
+		value = value[1:]
+	}
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validatePath vérifie la syntaxe des paramètres d'un chemin de route :
+// un :param doit être nommé, un *wildcard doit occuper le dernier segment
+func validatePath(path string) {
+	if path == "" || path[0] != '/' {
+		panic("gofsen: route path must begin with '/' in '" + path + "'")
+	}
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case ':':
+			name, constraint, end := paramToken(path[i:])
+			if name == "" {
+				panic("gofsen: parameter must be named in '" + path + "'")
+			}
+			if end < len(path[i:]) && path[i+end] != '/' {
+				panic("gofsen: parameter must span a whole path segment in '" + path + "'")
+			}
+			compileConstraint(constraint)
+			i += end - 1
+		case '*':
+			if path[i-1] != '/' {
+				panic("gofsen: wildcard must start a path segment in '" + path + "'")
+			}
+			if strings.IndexByte(path[i:], '/') >= 0 {
+				panic("gofsen: wildcard must be the last path segment in '" + path + "'")
+			}
+			return
+		}
+	}
+}
+
+// parseParams extrait les noms des paramètres (:param et *wildcard) d'un chemin
+func parseParams(path string) []string {
+	var params []string
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case ':':
+			name, _, end := paramToken(path[i:])
+			params = append(params, name)
+			i += end - 1
+		case '*':
+			name := path[i+1:]
+			if name == "" {
+				name = "*"
+			}
+			return append(params, name)
+		}
+	}
+	return params
+}
+
+// patternSegment segment d'un motif de route : un littéral, ou un préfixe
+// littéral suivi d'un :param ou d'un *wildcard
+type patternSegment struct {
+	kind       nodeKind
+	prefix     string
+	constraint string
+	check      func(string) bool
+}
+
+// splitPattern découpe un chemin de route en segments
+func splitPattern(path string) []patternSegment {
+	var segments []patternSegment
+	for path != "" {
+		path = path[1:]
+		end := strings.IndexAny(path, "/:*")
+		if end < 0 {
+			end = len(path)
+		}
+		segment := patternSegment{kind: staticKind, prefix: path[:end]}
+		path = path[end:]
+
+		switch {
+		case strings.HasPrefix(path, ":"):
+			_, constraint, end := paramToken(path)
+			segment.kind = paramKind
+			segment.constraint = constraint
+			segment.check = compileConstraint(constraint)
+			path = path[end:]
+		case strings.HasPrefix(path, "*"):
+			segment.kind = anyKind
+			path = ""
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// samePattern indique si deux motifs ne diffèrent que par le nom de leurs paramètres
+func samePattern(a, b []patternSegment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].kind != b[i].kind || a[i].prefix != b[i].prefix || a[i].constraint != b[i].constraint {
+			return false
+		}
+	}
+	return true
+}
+
+// patternsOverlap indique si une même requête peut correspondre aux deux motifs,
+// en les comparant segment par segment. Deux paramètres compatibles par leur
+// préfixe sont considérés comme chevauchants, quelles que soient leurs contraintes.
+func patternsOverlap(a, b []patternSegment) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].kind == anyKind || b[i].kind == anyKind {
+			return true
+		}
+		if !segmentsOverlap(a[i], b[i]) {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// segmentsOverlap indique si une même valeur de segment peut correspondre à x et y
+func segmentsOverlap(x, y patternSegment) bool {
+	if x.kind == staticKind && y.kind == staticKind {
+		return x.prefix == y.prefix
+	}
+	if x.kind == staticKind {
+		x, y = y, x
+	}
+	if y.kind == staticKind {
+		// Le paramètre capture au moins un caractère après son préfixe
+		if len(y.prefix) <= len(x.prefix) || !strings.HasPrefix(y.prefix, x.prefix) {
+			return false
+		}
+		return x.check == nil || x.check(y.prefix[len(x.prefix):])
+	}
+	return strings.HasPrefix(x.prefix, y.prefix) || strings.HasPrefix(y.prefix, x.prefix)
+}
+
+// RouteConflictError décrit deux routes qui répondent aux mêmes requêtes
+type RouteConflictError struct {
+	Route     Route // route en cours d'enregistrement
+	Existing  Route // route déjà enregistrée
+	Ambiguous bool  // chevauchement partiel (mode strict) plutôt que doublon
+}
+
+func newConflictError(route, existing *Route, ambiguous bool) *RouteConflictError {
+	return &RouteConflictError{Route: *route, Existing: *existing, Ambiguous: ambiguous}
+}
+
+func (e *RouteConflictError) Error() string {
+	if e.Ambiguous {
+		return "gofsen: ambiguous route " + e.Route.Method + " " + e.Route.Path +
+			" overlaps with " + e.Existing.Method + " " + e.Existing.Path
+	}
+	return "gofsen: duplicate route " + e.Route.Method + " " + e.Route.Path +
+		" conflicts with " + e.Existing.Method + " " + e.Existing.Path
+}
@@ -0,0 +1,387 @@
+package gofsen
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTreeMatching(t *testing.T) {
+	app := New()
+	noop := func(c *Context) {}
+
+	app.GET("/", noop)
+	app.GET("/users", noop)
+	app.GET("/users/me", noop)
+	app.GET("/users/:id", noop)
+	app.GET("/users/:id/posts/:post", noop)
+	app.GET("/uploads/*filepath", noop)
+	app.GET("/u", noop)
+	app.GET("/user-groups", noop)
+
+	tests := []struct {
+		path   string
+		route  string
+		params map[string]string
+	}{
+		{"/", "/", nil},
+		{"/users", "/users", nil},
+		{"/users/me", "/users/me", nil},
+		{"/users/42", "/users/:id", map[string]string{"id": "42"}},
+		{"/users/mex", "/users/:id", map[string]string{"id": "mex"}},
+		{"/users/42/posts/7", "/users/:id/posts/:post", map[string]string{"id": "42", "post": "7"}},
+		{"/uploads/a/b.png", "/uploads/*filepath", map[string]string{"filepath": "a/b.png"}},
+		{"/u", "/u", nil},
+		{"/user-groups", "/user-groups", nil},
+		{"/users/", "", nil},
+		{"/users/42/posts", "", nil},
+		{"/missing", "", nil},
+	}
+
+	for _, tt := range tests {
+		route, params := app.findRoute("GET", tt.path)
+		if tt.route == "" {
+			if route != nil {
+				t.Errorf("%s: expected no match, got %s", tt.path, route.Path)
+			}
+			continue
+		}
+		if route == nil {
+			t.Errorf("%s: expected %s, got no match", tt.path, tt.route)
+			continue
+		}
+		if route.Path != tt.route {
+			t.Errorf("%s: expected %s, got %s", tt.path, tt.route, route.Path)
+		}
+		for key, want := range tt.params {
+			if params[key] != want {
+				t.Errorf("%s: expected param %s=%q, got %q", tt.path, key, want, params[key])
+			}
+		}
+	}
+}
+
+func TestTreeMethodIsolation(t *testing.T) {
+	app := New()
+	app.GET("/items", func(c *Context) { c.Text("get") })
+	app.POST("/items", func(c *Context) { c.Text("post") })
+
+	req := httptest.NewRequest("POST", "/items", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Body.String() != "post" {
+		t.Errorf("Expected body 'post', got '%s'", w.Body.String())
+	}
+}
+
+func TestTreeStaticLookupDoesNotAllocate(t *testing.T) {
+	app := New()
+	registerBenchRoutes(app, 300)
+	root := app.trees["GET"]
+
+	allocs := testing.AllocsPerRun(100, func() {
+		root.match("/resource150/items", nil)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected 0 allocations for a static lookup, got %v", allocs)
+	}
+}
+
+// registerBenchRoutes enregistre n ressources, chacune avec une route statique et une route paramétrée
+func registerBenchRoutes(app *Router, n int) {
+	noop := func(c *Context) {}
+	for i := 0; i < n/2; i++ {
+		app.GET(fmt.Sprintf("/resource%d/items", i), noop)
+		app.GET(fmt.Sprintf("/resource%d/items/:id", i), noop)
+	}
+}
+
+// linearRouter reproduit l'ancienne recherche linéaire par regex, utilisée comme référence
+type linearRouter struct {
+	routes []linearRoute
+}
+
+type linearRoute struct {
+	method  string
+	path    string
+	pattern *regexp.Regexp
+	params  []string
+}
+
+func (l *linearRouter) add(method, path string) {
+	route := linearRoute{method: method, path: path}
+	if strings.Contains(path, ":") {
+		paramRegex := regexp.MustCompile(`:([^/]+)`)
+		regexPath := path
+		for _, match := range paramRegex.FindAllStringSubmatch(path, -1) {
+			route.params = append(route.params, match[1])
+			regexPath = strings.Replace(regexPath, match[0], "([^/]+)", 1)
+		}
+		route.pattern = regexp.MustCompile("^" + regexPath + "$")
+	}
+	l.routes = append(l.routes, route)
+}
+
+func (l *linearRouter) find(method, path string) (*linearRoute, map[string]string) {
+	for _, route := range l.routes {
+		if route.method != method {
+			continue
+		}
+		if route.pattern != nil {
+			if matches := route.pattern.FindStringSubmatch(path); matches != nil {
+				params := make(map[string]string)
+				for i, param := range route.params {
+					params[param] = matches[i+1]
+				}
+				return &route, params
+			}
+		} else if route.path == path {
+			return &route, make(map[string]string)
+		}
+	}
+	return nil, nil
+}
+
+func newBenchLinear(n int) *linearRouter {
+	l := &linearRouter{}
+	for i := 0; i < n/2; i++ {
+		l.add("GET", fmt.Sprintf("/resource%d/items", i))
+		l.add("GET", fmt.Sprintf("/resource%d/items/:id", i))
+	}
+	return l
+}
+
+func BenchmarkTreeStatic(b *testing.B) {
+	app := New()
+	registerBenchRoutes(app, 300)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app.findRoute("GET", "/resource149/items")
+	}
+}
+
+func BenchmarkLinearStatic(b *testing.B) {
+	l := newBenchLinear(300)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.find("GET", "/resource149/items")
+	}
+}
+
+func BenchmarkTreeParam(b *testing.B) {
+	app := New()
+	registerBenchRoutes(app, 300)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app.findRoute("GET", "/resource149/items/42")
+	}
+}
+
+func BenchmarkLinearParam(b *testing.B) {
+	l := newBenchLinear(300)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.find("GET", "/resource149/items/42")
+	}
+}
+
+func TestWildcardPrecedence(t *testing.T) {
+	app := New()
+	noop := func(c *Context) {}
+
+	app.GET("/files/readme", noop)
+	app.GET("/files/:name/meta", noop)
+	app.GET("/files/*filepath", noop)
+	app.GET("/proxy/*", noop)
+
+	tests := []struct {
+		path  string
+		route string
+		key   string
+		value string
+	}{
+		{"/files/readme", "/files/readme", "", ""},
+		{"/files/a.txt/meta", "/files/:name/meta", "name", "a.txt"},
+		{"/files/a.txt", "/files/*filepath", "filepath", "a.txt"},
+		{"/files/a/b/c.txt", "/files/*filepath", "filepath", "a/b/c.txt"},
+		{"/files/readme/other", "/files/*filepath", "filepath", "readme/other"},
+		{"/files/", "/files/*filepath", "filepath", ""},
+		{"/proxy/v1/users", "/proxy/*", "*", "v1/users"},
+	}
+
+	for _, tt := range tests {
+		route, params := app.findRoute("GET", tt.path)
+		if route == nil {
+			t.Errorf("%s: expected %s, got no match", tt.path, tt.route)
+			continue
+		}
+		if route.Path != tt.route {
+			t.Errorf("%s: expected %s, got %s", tt.path, tt.route, route.Path)
+		}
+		if tt.key != "" && params[tt.key] != tt.value {
+			t.Errorf("%s: expected param %s=%q, got %q", tt.path, tt.key, tt.value, params[tt.key])
+		}
+	}
+
+	if route, _ := app.findRoute("GET", "/files"); route != nil {
+		t.Errorf("/files: expected no match, got %s", route.Path)
+	}
+}
+
+func TestInvalidRoutePathsPanic(t *testing.T) {
+	paths := []string{
+		"users",
+		"/users/:",
+		"/users/:/posts",
+		"/files/*filepath/meta",
+		"/files*filepath",
+	}
+
+	for _, path := range paths {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected registration to panic", path)
+				}
+			}()
+			New().GET(path, func(c *Context) {})
+		}()
+	}
+}
+
+func TestConstrainedParams(t *testing.T) {
+	app := New()
+	noop := func(c *Context) {}
+
+	app.GET("/users/:id<int>", noop)
+	app.GET("/users/:name", noop)
+	app.GET("/posts/:slug<[a-z-]+>", noop)
+	app.GET("/files/:uuid<uuid>", noop)
+	app.GET("/codes/:code<(?P<x>[A-Z]{2})>/info", noop)
+
+	tests := []struct {
+		path  string
+		route string
+		key   string
+		value string
+	}{
+		{"/users/42", "/users/:id<int>", "id", "42"},
+		{"/users/-7", "/users/:id<int>", "id", "-7"},
+		{"/users/alice", "/users/:name", "name", "alice"},
+		{"/posts/hello-world", "/posts/:slug<[a-z-]+>", "slug", "hello-world"},
+		{"/files/0b8e5c3a-6d2f-4c1e-9a7b-3f2d1c0e9b8a", "/files/:uuid<uuid>", "uuid", "0b8e5c3a-6d2f-4c1e-9a7b-3f2d1c0e9b8a"},
+		{"/codes/FR/info", "/codes/:code<(?P<x>[A-Z]{2})>/info", "code", "FR"},
+		{"/posts/Hello_World", "", "", ""},
+		{"/files/not-a-uuid", "", "", ""},
+		{"/codes/fra/info", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		route, params := app.findRoute("GET", tt.path)
+		if tt.route == "" {
+			if route != nil {
+				t.Errorf("%s: expected no match, got %s", tt.path, route.Path)
+			}
+			continue
+		}
+		if route == nil {
+			t.Errorf("%s: expected %s, got no match", tt.path, tt.route)
+			continue
+		}
+		if route.Path != tt.route {
+			t.Errorf("%s: expected %s, got %s", tt.path, tt.route, route.Path)
+		}
+		if params[tt.key] != tt.value {
+			t.Errorf("%s: expected param %s=%q, got %q", tt.path, tt.key, tt.value, params[tt.key])
+		}
+	}
+}
+
+func TestInvalidConstraintsPanic(t *testing.T) {
+	paths := []string{
+		"/users/:id<[a-z>",
+		"/users/:id<(>",
+		"/users/:id<int>.json",
+	}
+
+	for _, path := range paths {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected registration to panic", path)
+				}
+			}()
+			New().GET(path, func(c *Context) {})
+		}()
+	}
+}
+
+// registrationPanic enregistre les routes et retourne la valeur du panic éventuel
+func registrationPanic(strict bool, paths ...string) (recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+	app := New()
+	app.SetStrict(strict)
+	for _, path := range paths {
+		app.GET(path, func(c *Context) {})
+	}
+	return nil
+}
+
+func TestRouteConflicts(t *testing.T) {
+	tests := []struct {
+		paths     []string
+		strict    bool
+		conflict  bool
+		ambiguous bool
+	}{
+		{[]string{"/users", "/users"}, false, true, false},
+		{[]string{"/users/:id", "/users/:name"}, false, true, false},
+		{[]string{"/users/:id<int>", "/users/:n<int>"}, false, true, false},
+		{[]string{"/files/*a", "/files/*b"}, false, true, false},
+		{[]string{"/users/:id", "/users/me"}, false, false, false},
+		{[]string{"/users/:id<int>", "/users/:name"}, false, false, false},
+		{[]string{"/users/:id", "/users/me"}, true, true, true},
+		{[]string{"/users/me", "/users/:id"}, true, true, true},
+		{[]string{"/users/:id<int>", "/users/:name"}, true, true, true},
+		{[]string{"/files/:name", "/files/*path"}, true, true, true},
+		{[]string{"/users/:id", "/posts/:id", "/users/:id/posts"}, true, false, false},
+		{[]string{"/users/list", "/:slug"}, true, false, false},
+		{[]string{"/a/b", "/a/:x/c"}, true, false, false},
+		{[]string{"/users/:id<int>", "/users/me"}, true, false, false},
+		{[]string{"/files/*path", "/files"}, true, false, false},
+		{[]string{"/users/:id", "/:section/42"}, true, true, true},
+		{[]string{"/files/*path", "/files/readme"}, true, true, true},
+	}
+
+	for _, tt := range tests {
+		recovered := registrationPanic(tt.strict, tt.paths...)
+		if !tt.conflict {
+			if recovered != nil {
+				t.Errorf("%v (strict=%v): unexpected panic: %v", tt.paths, tt.strict, recovered)
+			}
+			continue
+		}
+
+		err, ok := recovered.(*RouteConflictError)
+		if !ok {
+			t.Errorf("%v (strict=%v): expected a *RouteConflictError panic, got %v", tt.paths, tt.strict, recovered)
+			continue
+		}
+		if err.Ambiguous != tt.ambiguous {
+			t.Errorf("%v (strict=%v): expected ambiguous=%v, got %v", tt.paths, tt.strict, tt.ambiguous, err.Ambiguous)
+		}
+		msg := err.Error()
+		if !strings.Contains(msg, tt.paths[0]) || !strings.Contains(msg, tt.paths[1]) {
+			t.Errorf("%v: expected both routes in the error, got '%s'", tt.paths, msg)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package gofsen
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Named donne un nom à la route pour pouvoir générer son URL avec Router.URL
+func (route *Route) Named(name string) *Route {
+	if existing, ok := route.router.names[name]; ok && existing != route {
+		panic(fmt.Sprintf("gofsen: route name %q already used by %s %s", name, existing.Method, existing.Path))
+	}
+	if route.Name != "" {
+		delete(route.router.names, route.Name)
+	}
+	route.Name = name
+	route.router.names[name] = route
+	return route
+}
+
+// URL reconstruit le chemin de la route nommée name à partir de paires
+// clé/valeur de paramètres, ex: app.URL("user", "id", "42") -> "/users/42".
+// Les valeurs sont échappées et doivent respecter les contraintes de la route.
+func (r *Router) URL(name string, params ...string) (string, error) {
+	route, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("gofsen: no route named %q", name)
+	}
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("gofsen: odd number of parameters for route %q", name)
+	}
+
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+	return buildURL(route.Path, values)
+}
+
+// URLFor génère l'URL d'une route nommée du router qui traite la requête
+func (c *Context) URLFor(name string, params ...string) (string, error) {
+	if c.router == nil {
+		return "", fmt.Errorf("gofsen: no router attached to the context")
+	}
+	return c.router.URL(name, params...)
+}
+
+// buildURL remplace les :param et *wildcard du motif par leurs valeurs échappées
+func buildURL(pattern string, values map[string]string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case ':':
+			name, constraint, end := paramToken(pattern[i:])
+			value, ok := values[name]
+			if !ok || value == "" {
+				return "", fmt.Errorf("%w %q for %s", ErrMissingParam, name, pattern)
+			}
+			if check := compileConstraint(constraint); check != nil && !check(value) {
+				return "", fmt.Errorf("gofsen: parameter %q value %q does not satisfy <%s>", name, value, constraint)
+			}
+			b.WriteString(url.PathEscape(value))
+			i += end - 1
+		case '*':
+			name := pattern[i+1:]
+			if name == "" {
+				name = "*"
+			}
+			value, ok := values[name]
+			if !ok {
+				return "", fmt.Errorf("%w %q for %s", ErrMissingParam, name, pattern)
+			}
+			// Le wildcard peut contenir plusieurs segments : on échappe chacun d'eux
+			segments := strings.Split(value, "/")
+			for j, segment := range segments {
+				segments[j] = url.PathEscape(segment)
+			}
+			b.WriteString(strings.Join(segments, "/"))
+			return b.String(), nil
+		default:
+			b.WriteByte(pattern[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,88 @@
+package gofsen
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouterURL(t *testing.T) {
+	app := New()
+	noop := func(c *Context) {}
+
+	app.GET("/users/:id<int>", noop).Named("user")
+	api := app.Group("/api")
+	api.GET("/posts/:slug/comments/:comment", noop).Named("comment")
+	app.GET("/files/*filepath", noop).Named("file")
+
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{"user", []string{"id", "42"}, "/users/42"},
+		{"comment", []string{"slug", "hello world", "comment", "a/b"}, "/api/posts/hello%20world/comments/a%2Fb"},
+		{"file", []string{"filepath", "docs/read me.txt"}, "/files/docs/read%20me.txt"},
+	}
+
+	for _, tt := range tests {
+		got, err := app.URL(tt.name, tt.params...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected '%s', got '%s'", tt.name, tt.want, got)
+		}
+	}
+
+	if _, err := app.URL("user"); !errors.Is(err, ErrMissingParam) {
+		t.Errorf("Expected ErrMissingParam, got %v", err)
+	}
+	if _, err := app.URL("user", "id", "abc"); err == nil {
+		t.Error("Expected an error for a value violating the constraint")
+	}
+	if _, err := app.URL("user", "id"); err == nil {
+		t.Error("Expected an error for an odd number of parameters")
+	}
+	if _, err := app.URL("unknown"); err == nil {
+		t.Error("Expected an error for an unknown route name")
+	}
+}
+
+func TestDuplicateRouteNamePanics(t *testing.T) {
+	app := New()
+	app.GET("/a", func(c *Context) {}).Named("dup")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a duplicate route name")
+		}
+	}()
+	app.GET("/b", func(c *Context) {}).Named("dup")
+}
+
+func TestContextURLFor(t *testing.T) {
+	app := New()
+	app.GET("/users/:id", func(c *Context) {}).Named("user")
+	app.POST("/users", func(c *Context) {
+		location, err := c.URLFor("user", "id", "7")
+		if err != nil {
+			c.Error(500, err.Error())
+			return
+		}
+		c.ResponseWriter.Header().Set("Location", location)
+		c.Status(201)
+	})
+
+	req := httptest.NewRequest("POST", "/users", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 201 {
+		t.Errorf("Expected status 201, got %d", w.Code)
+	}
+	if location := w.Header().Get("Location"); location != "/users/7" {
+		t.Errorf("Expected Location '/users/7', got '%s'", location)
+	}
+}
@@ -0,0 +1,896 @@
+package gofsen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError décrit une violation du contrat OpenAPI
+type ValidationError struct {
+	In      string `json:"in"`              // path, query, header, cookie, body ou response
+	Field   string `json:"field,omitempty"` // nom du paramètre ou pointeur JSON dans le corps (ex: /address/city)
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.In + ": " + e.Message
+	}
+	return e.In + " " + e.Field + ": " + e.Message
+}
+
+// ValidationConfig configuration du middleware de validation OpenAPI
+type ValidationConfig struct {
+	// ValidateResponses vérifie aussi le statut, le Content-Type et le corps JSON
+	// des réponses. La réponse est alors mise en mémoire tampon : à réserver aux tests.
+	ValidateResponses bool
+
+	// OnResponseError reçoit les violations d'une réponse, qui est ensuite envoyée
+	// telle quelle. Si nil, la réponse est remplacée par une erreur 500 qui les liste.
+	OnResponseError func(c *Context, errs []ValidationError)
+
+	// MaxBodySize taille maximale en octets du corps lu pour la validation,
+	// 10 Mo par défaut. Un corps plus grand reçoit une erreur 413.
+	MaxBodySize int64
+}
+
+// defaultMaxBodySize limite par défaut du corps lu par le middleware de validation
+const defaultMaxBodySize = 10 << 20
+
+// LoadOpenAPI lit un document OpenAPI 3 en JSON ou en YAML
+func LoadOpenAPI(path string) (OpenAPIDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseOpenAPI(data)
+}
+
+// ParseOpenAPI analyse un document OpenAPI 3 en JSON ou en YAML
+func ParseOpenAPI(data []byte) (OpenAPIDocument, error) {
+	var doc interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if doc, err = parseYAML(data); err != nil {
+			return nil, err
+		}
+	}
+
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("gofsen: openapi document must be an object")
+	}
+	if version, _ := m["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("gofsen: unsupported openapi version %q", m["openapi"])
+	}
+	return OpenAPIDocument(m), nil
+}
+
+// ValidateOpenAPI valide les requêtes selon le document OpenAPI :
+// paramètres de chemin, de query, d'en-tête et de cookie, et corps JSON.
+// Les requêtes invalides reçoivent une erreur 400 listant chaque violation ;
+// celles qui ne correspondent à aucune opération du document passent sans contrôle.
+func ValidateOpenAPI(doc OpenAPIDocument) MiddlewareFunc {
+	return ValidateOpenAPIWithConfig(doc, ValidationConfig{})
+}
+
+// ValidateOpenAPIWithConfig valide les requêtes (et éventuellement les réponses)
+// selon le document OpenAPI avec une configuration personnalisée
+func ValidateOpenAPIWithConfig(doc OpenAPIDocument, config ValidationConfig) MiddlewareFunc {
+	v, err := newSpecValidator(doc)
+	if err != nil {
+		panic("gofsen: openapi validator: " + err.Error())
+	}
+	maxBodySize := config.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+
+	return func(c *Context) {
+		op, item, params := v.findOperation(c.Request)
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		if op["requestBody"] != nil && c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.ResponseWriter, c.Request.Body, maxBodySize)
+		}
+		errs, err := v.validateRequest(c.Request, op, item, params)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.renderError(413, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit), nil)
+			return
+		}
+		if len(errs) > 0 {
+			c.renderError(400, "Request validation failed", map[string]interface{}{"errors": errs})
+			return
+		}
+
+		if !config.ValidateResponses {
+			c.Next()
+			return
+		}
+
+		w := c.ResponseWriter
+		recorder := &responseRecorder{header: make(http.Header)}
+		c.ResponseWriter = recorder
+		c.Next()
+		c.ResponseWriter = w
+
+		errs = v.validateResponse(op, recorder)
+		if len(errs) > 0 && config.OnResponseError == nil {
+			c.renderError(500, "Response validation failed", map[string]interface{}{"errors": errs})
+			return
+		}
+		if len(errs) > 0 {
+			config.OnResponseError(c, errs)
+		}
+		recorder.flush(w)
+	}
+}
+
+// specValidator document OpenAPI préparé pour la validation
+type specValidator struct {
+	doc       map[string]interface{}
+	basePaths []string
+	paths     []specPath
+	patterns  sync.Map // expressions "pattern" compilées
+}
+
+// specPath chemin du document découpé en segments ("{id}" pour un paramètre)
+type specPath struct {
+	segments []string
+	item     map[string]interface{}
+	static   int
+}
+
+func newSpecValidator(doc OpenAPIDocument) (*specValidator, error) {
+	// Normaliser le document (ex: celui généré par Router.OpenAPI) en valeurs JSON génériques
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	v := &specValidator{doc: generic}
+
+	servers, _ := generic["servers"].([]interface{})
+	for _, server := range servers {
+		raw, _ := asObject(server)["url"].(string)
+		if u, err := url.Parse(raw); err == nil && !strings.Contains(raw, "{") {
+			if base := strings.TrimSuffix(u.Path, "/"); base != "" {
+				v.basePaths = append(v.basePaths, base)
+			}
+		}
+	}
+
+	paths, _ := generic["paths"].(map[string]interface{})
+	for p, item := range paths {
+		sp := specPath{segments: strings.Split(strings.Trim(p, "/"), "/"), item: v.resolve(item)}
+		for _, segment := range sp.segments {
+			if !strings.HasPrefix(segment, "{") {
+				sp.static++
+			}
+		}
+		v.paths = append(v.paths, sp)
+	}
+	// Les chemins concrets passent avant les chemins paramétrés
+	sort.SliceStable(v.paths, func(i, j int) bool {
+		if v.paths[i].static != v.paths[j].static {
+			return v.paths[i].static > v.paths[j].static
+		}
+		return strings.Join(v.paths[i].segments, "/") < strings.Join(v.paths[j].segments, "/")
+	})
+	return v, nil
+}
+
+// findOperation retourne l'opération correspondant à la requête et les valeurs des paramètres de chemin
+func (v *specValidator) findOperation(req *http.Request) (op, item map[string]interface{}, params map[string]string) {
+	p := req.URL.EscapedPath()
+	for _, base := range v.basePaths {
+		if p == base || strings.HasPrefix(p, base+"/") {
+			p = strings.TrimPrefix(p, base)
+			break
+		}
+	}
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+
+	method := strings.ToLower(req.Method)
+	for _, sp := range v.paths {
+		params, ok := sp.match(segments)
+		if !ok {
+			continue
+		}
+		op := asObject(sp.item[method])
+		if op == nil && method == "head" {
+			op = asObject(sp.item["get"])
+		}
+		if op == nil {
+			continue
+		}
+		return op, sp.item, params
+	}
+	return nil, nil, nil
+}
+
+// match compare les segments de la requête à ceux du chemin
+func (sp specPath) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(sp.segments) {
+		return nil, false
+	}
+	var params map[string]string
+	for i, segment := range sp.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil || value == "" {
+				return nil, false
+			}
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[segment[1:len(segment)-1]] = value
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// validateRequest vérifie les paramètres et le corps de la requête. L'erreur
+// retournée signale un corps dépassant la taille maximale.
+func (v *specValidator) validateRequest(req *http.Request, op, item map[string]interface{}, pathParams map[string]string) ([]ValidationError, error) {
+	var errs []ValidationError
+	for _, param := range v.parameters(op, item) {
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		required, _ := param["required"].(bool)
+
+		var values []string
+		switch in {
+		case "path":
+			required = true
+			if value, ok := pathParams[name]; ok {
+				values = []string{value}
+			}
+		case "query":
+			values = req.URL.Query()[name]
+		case "header":
+			// Ces en-têtes sont décrits par d'autres champs de la spécification
+			switch strings.ToLower(name) {
+			case "accept", "content-type", "authorization":
+				continue
+			}
+			values = req.Header.Values(name)
+		case "cookie":
+			if cookie, err := req.Cookie(name); err == nil {
+				values = []string{cookie.Value}
+			}
+		default:
+			continue
+		}
+
+		if len(values) == 0 {
+			if required {
+				errs = append(errs, ValidationError{In: in, Field: name, Message: "is required"})
+			}
+			continue
+		}
+
+		schema := v.resolve(param["schema"])
+		if schema == nil {
+			continue
+		}
+		value, err := coerceParam(values, schema, v)
+		if err != nil {
+			errs = append(errs, ValidationError{In: in, Field: name, Message: err.Error()})
+			continue
+		}
+		v.validateValue(value, schema, "", func(pointer, message string) {
+			errs = append(errs, ValidationError{In: in, Field: name + pointer, Message: message})
+		})
+	}
+
+	bodyErrs, err := v.validateBody(req, op)
+	return append(errs, bodyErrs...), err
+}
+
+// parameters fusionne les paramètres du chemin et ceux de l'opération, qui les remplacent
+func (v *specValidator) parameters(op, item map[string]interface{}) []map[string]interface{} {
+	var params []map[string]interface{}
+	index := make(map[string]int)
+	for _, source := range []interface{}{item["parameters"], op["parameters"]} {
+		list, _ := source.([]interface{})
+		for _, raw := range list {
+			param := v.resolve(raw)
+			if param == nil {
+				continue
+			}
+			key := fmt.Sprint(param["in"], ":", param["name"])
+			if i, ok := index[key]; ok {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// validateBody vérifie le Content-Type et le corps JSON de la requête.
+// Le corps lu est remis en place pour le handler ; un corps trop grand est
+// retourné comme *http.MaxBytesError.
+func (v *specValidator) validateBody(req *http.Request, op map[string]interface{}) ([]ValidationError, error) {
+	body := v.resolve(op["requestBody"])
+	if body == nil {
+		return nil, nil
+	}
+
+	var data []byte
+	if req.Body != nil {
+		var err error
+		data, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, err
+		}
+		if err != nil {
+			return []ValidationError{{In: "body", Message: "cannot read body: " + err.Error()}}, nil
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	if len(data) == 0 {
+		if required, _ := body["required"].(bool); required {
+			return []ValidationError{{In: "body", Message: "is required"}}, nil
+		}
+		return nil, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	content, _ := body["content"].(map[string]interface{})
+	media, ok := findMediaType(content, mediaType)
+	if !ok {
+		return []ValidationError{{In: "header", Field: "Content-Type", Message: fmt.Sprintf("unsupported media type %q", mediaType)}}, nil
+	}
+	if !isJSONMediaType(mediaType) {
+		return nil, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return []ValidationError{{In: "body", Message: "invalid JSON: " + err.Error()}}, nil
+	}
+
+	var errs []ValidationError
+	if schema := v.resolve(media["schema"]); schema != nil {
+		v.validateValue(value, schema, "", func(pointer, message string) {
+			errs = append(errs, ValidationError{In: "body", Field: pointer, Message: message})
+		}, "readOnly")
+	}
+	return errs, nil
+}
+
+// validateResponse vérifie le statut et le corps d'une réponse enregistrée
+func (v *specValidator) validateResponse(op map[string]interface{}, rec *responseRecorder) []ValidationError {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	responses, _ := op["responses"].(map[string]interface{})
+	code := strconv.Itoa(rec.status)
+
+	response := v.resolve(responses[code])
+	if response == nil {
+		response = v.resolve(responses[code[:1]+"XX"])
+	}
+	if response == nil {
+		response = v.resolve(responses["default"])
+	}
+	if response == nil {
+		return []ValidationError{{In: "response", Field: "status", Message: fmt.Sprintf("status %d is not documented", rec.status)}}
+	}
+
+	content, _ := response["content"].(map[string]interface{})
+	if len(content) == 0 || rec.body.Len() == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(rec.header.Get("Content-Type"))
+	media, ok := findMediaType(content, mediaType)
+	if !ok {
+		return []ValidationError{{In: "response", Field: "Content-Type", Message: fmt.Sprintf("undocumented media type %q", mediaType)}}
+	}
+	if !isJSONMediaType(mediaType) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(rec.body.Bytes(), &value); err != nil {
+		return []ValidationError{{In: "response", Message: "invalid JSON: " + err.Error()}}
+	}
+
+	var errs []ValidationError
+	if schema := v.resolve(media["schema"]); schema != nil {
+		v.validateValue(value, schema, "", func(pointer, message string) {
+			errs = append(errs, ValidationError{In: "response", Field: pointer, Message: message})
+		}, "writeOnly")
+	}
+	return errs
+}
+
+// findMediaType cherche le type exact, puis type/*, puis */*
+func findMediaType(content map[string]interface{}, mediaType string) (map[string]interface{}, bool) {
+	if media, ok := content[mediaType]; ok {
+		return asObject(media), true
+	}
+	if i := strings.Index(mediaType, "/"); i > 0 {
+		if media, ok := content[mediaType[:i]+"/*"]; ok {
+			return asObject(media), true
+		}
+	}
+	media, ok := content["*/*"]
+	return asObject(media), ok
+}
+
+// isJSONMediaType reconnaît application/json et les types +json (ex: application/problem+json)
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// coerceParam convertit les valeurs textuelles d'un paramètre selon le type de son schéma
+func coerceParam(values []string, schema map[string]interface{}, v *specValidator) (interface{}, error) {
+	if schemaAllows(schema, "array") {
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		items := v.resolve(schema["items"])
+		list := make([]interface{}, len(values))
+		for i, value := range values {
+			item, err := coerceScalar(value, items)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %v", i, err)
+			}
+			list[i] = item
+		}
+		return list, nil
+	}
+	return coerceScalar(values[0], schema)
+}
+
+// coerceScalar convertit une valeur textuelle en nombre ou booléen si le schéma l'exige
+func coerceScalar(value string, schema map[string]interface{}) (interface{}, error) {
+	switch {
+	case schemaAllows(schema, "string"), schema == nil:
+		return value, nil
+	case schemaAllows(schema, "integer"):
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return float64(n), nil
+	case schemaAllows(schema, "number"):
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return n, nil
+	case schemaAllows(schema, "boolean"):
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+	}
+	return value, nil
+}
+
+// validateValue valide une valeur JSON générique contre un schéma. skip désigne
+// les propriétés requises qui peuvent manquer : readOnly dans les requêtes,
+// writeOnly dans les réponses.
+func (v *specValidator) validateValue(value interface{}, schema map[string]interface{}, pointer string, report func(pointer, message string), skip ...string) {
+	if schema == nil {
+		return
+	}
+
+	if value == nil && schema["nullable"] == true {
+		return
+	}
+	if types := schemaTypes(schema); len(types) > 0 && !typeMatches(value, types) {
+		report(pointer, "must be of type "+strings.Join(types, " or "))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		report(pointer, fmt.Sprintf("must be one of %s", formatValues(enum)))
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		report(pointer, fmt.Sprintf("must be %s", formatValues([]interface{}{constant})))
+	}
+
+	switch val := value.(type) {
+	case string:
+		v.validateString(val, schema, pointer, report)
+	case float64:
+		validateNumber(val, schema, pointer, report)
+	case []interface{}:
+		if n, ok := schemaNumber(schema, "minItems"); ok && float64(len(val)) < n {
+			report(pointer, fmt.Sprintf("must have at least %v items", n))
+		}
+		if n, ok := schemaNumber(schema, "maxItems"); ok && float64(len(val)) > n {
+			report(pointer, fmt.Sprintf("must have at most %v items", n))
+		}
+		if schema["uniqueItems"] == true {
+			for i := range val {
+				if containsValue(val[:i], val[i]) {
+					report(pointer, "items must be unique")
+					break
+				}
+			}
+		}
+		if items := v.resolve(schema["items"]); items != nil {
+			for i, item := range val {
+				v.validateValue(item, items, pointer+"/"+strconv.Itoa(i), report, skip...)
+			}
+		}
+	case map[string]interface{}:
+		v.validateObject(val, schema, pointer, report, skip)
+	}
+
+	for _, sub := range asList(schema["allOf"]) {
+		v.validateValue(value, v.resolve(sub), pointer, report, skip...)
+	}
+	if anyOf := asList(schema["anyOf"]); len(anyOf) > 0 && v.countMatches(value, anyOf, skip) == 0 {
+		report(pointer, "must match at least one schema in anyOf")
+	}
+	if oneOf := asList(schema["oneOf"]); len(oneOf) > 0 {
+		if n := v.countMatches(value, oneOf, skip); n != 1 {
+			report(pointer, fmt.Sprintf("must match exactly one schema in oneOf, matched %d", n))
+		}
+	}
+	if not := v.resolve(schema["not"]); not != nil && v.matches(value, not, skip) {
+		report(pointer, "must not match the schema in not")
+	}
+}
+
+// validateObject valide les propriétés requises, connues et additionnelles d'un objet
+func (v *specValidator) validateObject(obj, schema map[string]interface{}, pointer string, report func(pointer, message string), skip []string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	for _, raw := range asList(schema["required"]) {
+		name, _ := raw.(string)
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		if prop := v.resolve(properties[name]); prop != nil && hasFlag(prop, skip) {
+			continue
+		}
+		report(pointer+"/"+escapePointer(name), "is required")
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := pointer + "/" + escapePointer(name)
+		if raw, ok := properties[name]; ok {
+			v.validateValue(obj[name], v.resolve(raw), child, report, skip...)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				report(child, "is not allowed")
+			}
+		case map[string]interface{}:
+			v.validateValue(obj[name], v.resolve(extra), child, report, skip...)
+		}
+	}
+}
+
+// validateString valide longueur, motif et format d'une chaîne
+func (v *specValidator) validateString(s string, schema map[string]interface{}, pointer string, report func(pointer, message string)) {
+	length := float64(utf8.RuneCountInString(s))
+	if n, ok := schemaNumber(schema, "minLength"); ok && length < n {
+		report(pointer, fmt.Sprintf("must be at least %v characters", n))
+	}
+	if n, ok := schemaNumber(schema, "maxLength"); ok && length > n {
+		report(pointer, fmt.Sprintf("must be at most %v characters", n))
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		cached, found := v.patterns.Load(pattern)
+		if !found {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				cached = err
+			} else {
+				cached = re
+			}
+			v.patterns.Store(pattern, cached)
+		}
+		if re, ok := cached.(*regexp.Regexp); ok && !re.MatchString(s) {
+			report(pointer, "must match pattern "+pattern)
+		}
+	}
+
+	format, _ := schema["format"].(string)
+	valid := true
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		valid = err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		valid = err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		valid = err == nil && addr.Address == s
+	case "uuid":
+		_, err := ParseUUID(s)
+		valid = err == nil
+	case "uri":
+		u, err := url.Parse(s)
+		valid = err == nil && u.Scheme != ""
+	case "ipv4":
+		ip := net.ParseIP(s)
+		valid = ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(s)
+		valid = ip != nil && ip.To4() == nil
+	}
+	if !valid {
+		report(pointer, "must be a valid "+format)
+	}
+}
+
+// validateNumber valide bornes et multiple d'un nombre
+func validateNumber(n float64, schema map[string]interface{}, pointer string, report func(pointer, message string)) {
+	if schemaAllows(schema, "integer") && !schemaAllows(schema, "number") && n != math.Trunc(n) {
+		report(pointer, "must be an integer")
+	}
+
+	// OpenAPI 3.0 : exclusiveMinimum/exclusiveMaximum sont des booléens
+	if lower, ok := schemaNumber(schema, "minimum"); ok {
+		if schema["exclusiveMinimum"] == true && n <= lower {
+			report(pointer, fmt.Sprintf("must be greater than %v", lower))
+		} else if n < lower {
+			report(pointer, fmt.Sprintf("must be greater than or equal to %v", lower))
+		}
+	}
+	if upper, ok := schemaNumber(schema, "maximum"); ok {
+		if schema["exclusiveMaximum"] == true && n >= upper {
+			report(pointer, fmt.Sprintf("must be less than %v", upper))
+		} else if n > upper {
+			report(pointer, fmt.Sprintf("must be less than or equal to %v", upper))
+		}
+	}
+	// OpenAPI 3.1 : ce sont des nombres
+	if lower, ok := schemaNumber(schema, "exclusiveMinimum"); ok && n <= lower {
+		report(pointer, fmt.Sprintf("must be greater than %v", lower))
+	}
+	if upper, ok := schemaNumber(schema, "exclusiveMaximum"); ok && n >= upper {
+		report(pointer, fmt.Sprintf("must be less than %v", upper))
+	}
+	if m, ok := schemaNumber(schema, "multipleOf"); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			report(pointer, fmt.Sprintf("must be a multiple of %v", m))
+		}
+	}
+}
+
+// countMatches compte les schémas d'une liste auxquels la valeur est conforme
+func (v *specValidator) countMatches(value interface{}, schemas []interface{}, skip []string) int {
+	count := 0
+	for _, schema := range schemas {
+		if v.matches(value, v.resolve(schema), skip) {
+			count++
+		}
+	}
+	return count
+}
+
+// matches indique si la valeur est conforme au schéma, sans collecter les violations
+func (v *specValidator) matches(value interface{}, schema map[string]interface{}, skip []string) bool {
+	ok := true
+	v.validateValue(value, schema, "", func(string, string) { ok = false }, skip...)
+	return ok
+}
+
+// resolve suit les références locales ($ref: "#/components/...") et retourne l'objet cible
+func (v *specValidator) resolve(value interface{}) map[string]interface{} {
+	obj := asObject(value)
+	for depth := 0; obj != nil && depth < 32; depth++ {
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return obj
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil
+		}
+		var target interface{} = v.doc
+		for _, part := range strings.Split(ref[2:], "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			target = asObject(target)[part]
+		}
+		obj = asObject(target)
+	}
+	return obj
+}
+
+// schemaTypes retourne les types autorisés par le schéma ("type" chaîne ou liste)
+func schemaTypes(schema map[string]interface{}) []string {
+	var types []string
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	return types
+}
+
+// schemaAllows indique si le schéma autorise explicitement le type
+func schemaAllows(schema map[string]interface{}, typ string) bool {
+	for _, t := range schemaTypes(schema) {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// typeMatches indique si une valeur JSON générique est de l'un des types
+func typeMatches(value interface{}, types []string) bool {
+	for _, t := range types {
+		switch val := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || t == "integer" && val == math.Trunc(val) {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaNumber lit un mot-clé numérique du schéma
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
+
+// hasFlag indique si le schéma porte l'un des drapeaux (ex: readOnly)
+func hasFlag(schema map[string]interface{}, flags []string) bool {
+	for _, flag := range flags {
+		if schema[flag] == true {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValues(values []interface{}) string {
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// escapePointer échappe un nom de propriété pour un pointeur JSON (RFC 6901)
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+func asObject(value interface{}) map[string]interface{} {
+	obj, _ := value.(map[string]interface{})
+	return obj
+}
+
+func asList(value interface{}) []interface{} {
+	list, _ := value.([]interface{})
+	return list
+}
+
+// responseRecorder met une réponse en mémoire tampon pour pouvoir la valider avant envoi
+type responseRecorder struct {
+	header  http.Header
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+// WriteHeader mémorise le statut jusqu'à la première écriture, comme responseWriter
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.written {
+		return
+	}
+	r.status = code
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.written = true
+	return r.body.Write(b)
+}
+
+// Written indique si le corps de la réponse a commencé
+func (r *responseRecorder) Written() bool {
+	return r.written
+}
+
+// flush envoie la réponse enregistrée au ResponseWriter d'origine
+func (r *responseRecorder) flush(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(r.status)
+	w.Write(r.body.Bytes())
+}
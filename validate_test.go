@@ -0,0 +1,432 @@
+package gofsen
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const usersSpec = `openapi: 3.0.3
+info:
+  title: Users
+  version: "1.0"
+servers:
+  - url: https://api.example.com/api
+paths:
+  /users/me:
+    get:
+      responses:
+        "200":
+          description: Current user
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema: {type: integer, minimum: 1}
+    get:
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: array
+            items: {type: string, enum: [name, email]}
+        - name: X-Request-ID
+          in: header
+          required: true
+          schema: {type: string, format: uuid}
+      responses:
+        "200":
+          description: A user
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        "201":
+          description: Created
+components:
+  schemas:
+    User:
+      type: object
+      additionalProperties: false
+      required: [id, name, email]
+      properties:
+        id: {type: integer, readOnly: true}
+        name: {type: string, minLength: 2}
+        email: {type: string, format: email}
+        age: {type: integer, minimum: 0, maximum: 150}
+        tags:
+          type: array
+          items: {type: string}
+          uniqueItems: true
+`
+
+type validationResponse struct {
+	Error  string            `json:"error"`
+	Errors []ValidationError `json:"errors"`
+}
+
+func decodeValidation(t *testing.T, body string) validationResponse {
+	var resp validationResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("Invalid JSON response: %v (%s)", err, body)
+	}
+	return resp
+}
+
+func TestValidateOpenAPIValidRequests(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	app := New()
+	app.Use(ValidateOpenAPIWithConfig(doc, ValidationConfig{}))
+	api := app.Group("/api")
+	api.GET("/users/me", func(c *Context) { c.Text("me") })
+	api.GET("/users/:id", func(c *Context) {
+		c.JSON(map[string]interface{}{"id": 7, "name": "Jo", "email": "jo@example.com", "age": c.QueryParam("age")})
+	})
+	api.POST("/users", func(c *Context) {
+		var user map[string]interface{}
+		if err := c.BindJSON(&user); err != nil {
+			c.Error(500, err.Error())
+			return
+		}
+		c.Status(201).Text(user["name"].(string))
+	})
+	app.GET("/health", func(c *Context) { c.Text("ok") })
+
+	tests := []struct {
+		method string
+		target string
+		header string
+		body   string
+		code   int
+	}{
+		{"GET", "/api/users/me", "", "", 200},
+		{"GET", "/api/users/42?fields=name,email", "0b5ba7c4-5f0e-4b0e-9f43-6c2a4b7d2a11", "", 200},
+		{"GET", "/api/users/42?fields=name&fields=email", "0b5ba7c4-5f0e-4b0e-9f43-6c2a4b7d2a11", "", 200},
+		{"POST", "/api/users", "", `{"name":"Ada","email":"ada@example.com","tags":["a","b"]}`, 201},
+		{"GET", "/health", "", "", 200},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		}
+		if tt.header != "" {
+			req.Header.Set("X-Request-ID", tt.header)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s %s: expected status %d, got %d (%s)", tt.method, tt.target, tt.code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestValidateOpenAPIRequestErrors(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	app := New()
+	app.Use(ValidateOpenAPIWithConfig(doc, ValidationConfig{}))
+	api := app.Group("/api")
+	api.GET("/users/:id", func(c *Context) {})
+	api.POST("/users", func(c *Context) {})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header string
+		body   string
+		want   []ValidationError
+	}{
+		{
+			name:   "parameters",
+			method: "GET",
+			target: "/api/users/0?fields=name,phone",
+			header: "not-a-uuid",
+			want: []ValidationError{
+				{In: "path", Field: "id", Message: "must be greater than or equal to 1"},
+				{In: "query", Field: "fields/1", Message: `must be one of ["name","email"]`},
+				{In: "header", Field: "X-Request-ID", Message: "must be a valid uuid"},
+			},
+		},
+		{
+			name:   "missing header and bad type",
+			method: "GET",
+			target: "/api/users/abc",
+			want: []ValidationError{
+				{In: "path", Field: "id", Message: "must be an integer"},
+				{In: "header", Field: "X-Request-ID", Message: "is required"},
+			},
+		},
+		{
+			name:   "body",
+			method: "POST",
+			target: "/api/users",
+			body:   `{"name":"A","email":"nope","age":200.5,"tags":["a","a"],"admin":true}`,
+			want: []ValidationError{
+				{In: "body", Field: "/admin", Message: "is not allowed"},
+				{In: "body", Field: "/age", Message: "must be of type integer"},
+				{In: "body", Field: "/email", Message: "must be a valid email"},
+				{In: "body", Field: "/name", Message: "must be at least 2 characters"},
+				{In: "body", Field: "/tags", Message: "items must be unique"},
+			},
+		},
+		{
+			name:   "missing body",
+			method: "POST",
+			target: "/api/users",
+			want:   []ValidationError{{In: "body", Message: "is required"}},
+		},
+		{
+			name:   "invalid JSON",
+			method: "POST",
+			target: "/api/users",
+			body:   `{"name":`,
+			want:   []ValidationError{{In: "body", Message: "invalid JSON: unexpected end of JSON input"}},
+		},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		if tt.header != "" {
+			req.Header.Set("X-Request-ID", tt.header)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != 400 {
+			t.Errorf("%s: expected status 400, got %d", tt.name, w.Code)
+			continue
+		}
+		resp := decodeValidation(t, w.Body.String())
+		if resp.Error != "Request validation failed" {
+			t.Errorf("%s: expected error 'Request validation failed', got '%s'", tt.name, resp.Error)
+		}
+		if !reflect.DeepEqual(resp.Errors, tt.want) {
+			t.Errorf("%s: expected errors %v, got %v", tt.name, tt.want, resp.Errors)
+		}
+	}
+}
+
+func TestValidateOpenAPIContentType(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	app := New()
+	app.Use(ValidateOpenAPIWithConfig(doc, ValidationConfig{}))
+	app.POST("/api/users", func(c *Context) {})
+
+	req := httptest.NewRequest("POST", "/api/users", strings.NewReader("name=Ada"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 400 {
+		t.Fatalf("Expected status 400, got %d", w.Code)
+	}
+	resp := decodeValidation(t, w.Body.String())
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "Content-Type" {
+		t.Errorf("Expected a Content-Type violation, got %v", resp.Errors)
+	}
+}
+
+func TestValidateOpenAPIBodySize(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	app := New()
+	app.Use(ValidateOpenAPIWithConfig(doc, ValidationConfig{MaxBodySize: 64}))
+	app.POST("/api/users", func(c *Context) { c.Status(201) })
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"name": "Ada", "email": "ada@example.com"}`, 201},
+		{`{"name": "Ada", "email": "ada@example.com", "tags": ["` + strings.Repeat("x", 64) + `"]}`, 413},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/api/users", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%d bytes: expected status %d, got %d", len(tt.body), tt.code, w.Code)
+		}
+	}
+}
+
+func TestValidateOpenAPIResponses(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	app := New()
+	app.Use(ValidateOpenAPIWithConfig(doc, ValidationConfig{ValidateResponses: true}))
+	api := app.Group("/api")
+	api.GET("/users/:id", func(c *Context) {
+		c.JSON(map[string]interface{}{"id": 7, "name": "Jo", "email": "jo@example.com", "age": c.QueryParam("age")})
+	})
+	api.POST("/users", func(c *Context) {
+		var user map[string]interface{}
+		if err := c.BindJSON(&user); err != nil {
+			c.Error(500, err.Error())
+			return
+		}
+		c.Status(201).Text(user["name"].(string))
+	})
+
+	// Le handler renvoie "age" sous forme de chaîne alors que le schéma attend un entier
+	req := httptest.NewRequest("GET", "/api/users/42", nil)
+	req.Header.Set("X-Request-ID", "0b5ba7c4-5f0e-4b0e-9f43-6c2a4b7d2a11")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 500 {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	resp := decodeValidation(t, w.Body.String())
+	want := []ValidationError{{In: "response", Field: "/age", Message: "must be of type integer"}}
+	if !reflect.DeepEqual(resp.Errors, want) {
+		t.Errorf("Expected errors %v, got %v", want, resp.Errors)
+	}
+
+	// 201 est documenté : la réponse passe sans modification
+	req = httptest.NewRequest("POST", "/api/users", strings.NewReader(`{"name":"Ada","email":"ada@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 201 || w.Body.String() != "Ada" {
+		t.Errorf("Expected 201 'Ada', got %d '%s'", w.Code, w.Body.String())
+	}
+}
+
+func TestValidateOpenAPIOnResponseError(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var reported []ValidationError
+	app := New()
+	app.Use(ValidateOpenAPIWithConfig(doc, ValidationConfig{
+		ValidateResponses: true,
+		OnResponseError:   func(c *Context, errs []ValidationError) { reported = errs },
+	}))
+	api := app.Group("/api")
+	api.GET("/users/me", func(c *Context) { c.Text("me") })
+	api.DELETE("/users/:id", func(c *Context) { c.Status(204) })
+
+	req := httptest.NewRequest("GET", "/api/users/me", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Body.String() != "me" {
+		t.Errorf("Expected original response, got %d '%s'", w.Code, w.Body.String())
+	}
+	if len(reported) != 0 {
+		t.Errorf("Expected no violations, got %v", reported)
+	}
+
+	// DELETE n'est pas dans le document : pas de validation
+	req = httptest.NewRequest("DELETE", "/api/users/1", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+}
+
+func TestValidateOpenAPIResponseStatus(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(usersSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Le statut final doit être le même avec ou sans validation des réponses
+	for _, validate := range []bool{false, true} {
+		app := New()
+		app.Use(ValidateOpenAPIWithConfig(doc, ValidationConfig{
+			ValidateResponses: validate,
+			OnResponseError:   func(c *Context, errs []ValidationError) {},
+		}))
+		app.GET("/api/users/:id", WrapE(func(c *Context) error {
+			c.Status(201)
+			return NewHTTPError(404, "user_not_found", "User not found")
+		}))
+
+		req := httptest.NewRequest("GET", "/api/users/7", nil)
+		req.Header.Set("X-Request-ID", "3f2504e0-4f89-11d3-9a0c-0305e82c3301")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != 404 {
+			t.Errorf("ValidateResponses=%v: expected status 404, got %d", validate, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "User not found") {
+			t.Errorf("ValidateResponses=%v: expected error body, got '%s'", validate, w.Body.String())
+		}
+	}
+}
+
+func TestValidateGeneratedOpenAPI(t *testing.T) {
+	app := New()
+	app.POST("/users", func(c *Context) {}).Accepts(apiUser{})
+	app.Use(ValidateOpenAPI(app.OpenAPI(OpenAPIConfig{Title: "Users"})))
+
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"id":1,"address":"Paris","created_at":"2024-01-02T03:04:05Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 400 {
+		t.Fatalf("Expected status 400, got %d", w.Code)
+	}
+	resp := decodeValidation(t, w.Body.String())
+	want := []ValidationError{
+		{In: "body", Field: "/name", Message: "is required"},
+		{In: "body", Field: "/address", Message: "must be of type object"},
+	}
+	if !reflect.DeepEqual(resp.Errors, want) {
+		t.Errorf("Expected errors %v, got %v", want, resp.Errors)
+	}
+}
+
+func TestParseOpenAPIErrors(t *testing.T) {
+	for _, data := range []string{`{"swagger":"2.0"}`, "- a\n- b\n", `{"openapi":`} {
+		if _, err := ParseOpenAPI([]byte(data)); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package gofsen
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionConfig configuration de la sélection de version des routes
+type VersionConfig struct {
+	Header  string // en-tête portant la version, "Accept-Version" par défaut
+	Vendor  string // vendeur des media types, ex: "acme" pour application/vnd.acme.v2+json (tout vendeur si vide)
+	Default string // version servie quand la requête n'en précise aucune
+}
+
+// Deprecation décrit le retrait programmé d'une version de l'API
+type Deprecation struct {
+	Date   time.Time // date de dépréciation, en-tête Deprecation (RFC 9745) ; "true" si zéro
+	Sunset time.Time // date de retrait, en-tête Sunset (RFC 8594)
+	Link   string    // documentation de migration, en-tête Link rel="deprecation"
+}
+
+// versionRouter regroupe les arbres de routage propres à une version
+type versionRouter struct {
+	name        string
+	trees       map[string]*node
+	deprecation *Deprecation
+}
+
+// SetVersioning configure la lecture de la version demandée par les requêtes
+func (r *Router) SetVersioning(config VersionConfig) {
+	r.versioning = config
+}
+
+// Version retourne un groupe dont les routes ne répondent qu'aux requêtes
+// demandant cette version, via l'en-tête Accept-Version (ex: "v2" ou "2"),
+// un media type vendeur (application/vnd.acme.v2+json), un paramètre
+// version (application/json; version=2) ou la version par défaut.
+// Une requête sans route pour sa version retombe sur les routes sans version.
+func (r *Router) Version(version string) *RouteGroup {
+	return &RouteGroup{
+		router:  r,
+		version: r.versionRouter(version),
+	}
+}
+
+// Version retourne un sous-groupe du groupe restreint à une version
+func (g *RouteGroup) Version(version string) *RouteGroup {
+	if g.host != nil {
+		panic("gofsen: versioned routes cannot be restricted to a host")
+	}
+	return &RouteGroup{
+		prefix:  g.prefix,
+		router:  g.router,
+		parent:  g,
+		version: g.router.versionRouter(version),
+	}
+}
+
+// DeprecateVersion marque une version comme dépréciée : ses routes sont
+// signalées dans la documentation et leurs réponses portent les en-têtes
+// Deprecation, Sunset et Link
+func (r *Router) DeprecateVersion(version string, deprecation Deprecation) {
+	v := r.versionRouter(version)
+	v.deprecation = &deprecation
+	for _, route := range r.routes {
+		if route.Version == v.name {
+			route.Deprecated = true
+		}
+	}
+}
+
+// Version retourne la version demandée par la requête, ou la version par défaut
+func (c *Context) Version() string {
+	return c.version
+}
+
+// versionRouter retourne la version enregistrée sous ce nom, en la créant si besoin
+func (r *Router) versionRouter(version string) *versionRouter {
+	key := normalizeVersion(version)
+	if key == "" {
+		panic("gofsen: empty version")
+	}
+	if v, ok := r.versions[key]; ok {
+		return v
+	}
+	v := &versionRouter{name: version, trees: make(map[string]*node)}
+	r.versions[key] = v
+	return v
+}
+
+// requestVersion retourne la version demandée par la requête : l'en-tête
+// configuré, puis l'en-tête Accept, puis la version par défaut
+func (r *Router) requestVersion(req *http.Request) string {
+	if version := strings.TrimSpace(req.Header.Get(r.versionHeader())); version != "" {
+		return version
+	}
+	if version := acceptVersion(req.Header.Get("Accept"), r.versioning.Vendor); version != "" {
+		return version
+	}
+	return r.versioning.Default
+}
+
+// acceptVersion extrait la version d'un en-tête Accept : media type vendeur
+// (application/vnd.acme.v2+json) ou paramètre version (application/json; version=2)
+func acceptVersion(accept, vendor string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if version := params["version"]; version != "" {
+			return version
+		}
+
+		rest, ok := strings.CutPrefix(mediaType, "application/vnd.")
+		if !ok {
+			continue
+		}
+		rest, _, _ = strings.Cut(rest, "+")
+		i := strings.LastIndexByte(rest, '.')
+		if i < 0 {
+			continue
+		}
+		if vendor != "" && !strings.EqualFold(rest[:i], vendor) {
+			continue
+		}
+		if version := rest[i+1:]; version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// findVersion retourne les routes de la version demandée, nil si elle n'est pas enregistrée
+func (r *Router) findVersion(version string) *versionRouter {
+	if version == "" || len(r.versions) == 0 {
+		return nil
+	}
+	return r.versions[normalizeVersion(version)]
+}
+
+// normalizeVersion rend "v2", "V2" et "2" équivalents
+func normalizeVersion(version string) string {
+	version = strings.ToLower(strings.TrimSpace(version))
+	if len(version) > 1 && version[0] == 'v' && version[1] >= '0' && version[1] <= '9' {
+		return version[1:]
+	}
+	return version
+}
+
+// setHeaders ajoute les en-têtes de dépréciation de la version
+func (d *Deprecation) setHeaders(header http.Header) {
+	if d.Date.IsZero() {
+		header.Set("Deprecation", "true")
+	} else {
+		header.Set("Deprecation", "@"+strconv.FormatInt(d.Date.Unix(), 10))
+	}
+	if !d.Sunset.IsZero() {
+		header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		header.Add("Link", "<"+d.Link+">; rel=\"deprecation\"")
+	}
+}
+
+// versionHeader retourne le nom de l'en-tête portant la version
+func (r *Router) versionHeader() string {
+	if r.versioning.Header != "" {
+		return r.versioning.Header
+	}
+	return "Accept-Version"
+}
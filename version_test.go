@@ -0,0 +1,163 @@
+package gofsen
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVersionSelection(t *testing.T) {
+	app := New()
+	app.SetVersioning(VersionConfig{Vendor: "acme", Default: "v2"})
+	app.Version("v1").GET("/users/:id", func(c *Context) { c.Text("v1 user " + c.Param("id")) })
+	app.Version("v2").GET("/users/:id", func(c *Context) { c.Text("v2 user " + c.Param("id")) })
+	api := app.Group("/api")
+	api.Version("v1").GET("/items", func(c *Context) { c.Text("v1 items") })
+	api.Version("v2").GET("/items", func(c *Context) { c.Text("v2 items " + c.Version()) })
+	app.GET("/health", func(c *Context) { c.Text("ok " + c.Version()) })
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		accept string
+		body   string
+	}{
+		{"default version", "/users/1", "", "", "v2 user 1"},
+		{"header", "/users/1", "v1", "", "v1 user 1"},
+		{"header without prefix", "/users/1", "1", "", "v1 user 1"},
+		{"vendor media type", "/users/1", "", "application/vnd.acme.v1+json", "v1 user 1"},
+		{"other vendor ignored", "/users/1", "", "application/vnd.other.v1+json", "v2 user 1"},
+		{"media type parameter", "/users/1", "", "text/html, application/json; version=1", "v1 user 1"},
+		{"header wins over Accept", "/users/1", "v2", "application/vnd.acme.v1+json", "v2 user 1"},
+		{"group prefix", "/api/items", "v1", "", "v1 items"},
+		{"group prefix default", "/api/items", "", "", "v2 items v2"},
+		{"unversioned fallback", "/health", "v1", "", "ok v1"},
+		{"unknown version fallback", "/health", "v9", "", "ok v9"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set("Accept-Version", tt.header)
+		}
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		if w.Code != 200 {
+			t.Errorf("%s: expected status 200, got %d", tt.name, w.Code)
+			continue
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("%s: expected body '%s', got '%s'", tt.name, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestVersionNotFound(t *testing.T) {
+	app := New()
+	app.SetVersioning(VersionConfig{Vendor: "acme", Default: "v2"})
+	app.Version("v1").GET("/users/:id", func(c *Context) {})
+	v2 := app.Version("v2")
+	v2.GET("/users/:id", func(c *Context) {})
+	v2.POST("/users", func(c *Context) { c.Status(201) })
+
+	// POST /users n'existe qu'en v2
+	req := httptest.NewRequest("POST", "/users", nil)
+	req.Header.Set("Accept-Version", "v1")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 404 {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/users", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 201 {
+		t.Errorf("Expected status 201 with default version, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("DELETE", "/users/1", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != 405 {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+		t.Errorf("Expected Allow 'GET, HEAD, OPTIONS', got '%s'", allow)
+	}
+}
+
+func TestDeprecateVersion(t *testing.T) {
+	app := New()
+	app.SetVersioning(VersionConfig{Vendor: "acme", Default: "v2"})
+	app.Version("v1").GET("/users/:id", func(c *Context) {})
+	app.Version("v2").GET("/users/:id", func(c *Context) {})
+	sunset := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	app.DeprecateVersion("v1", Deprecation{
+		Date:   time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		Sunset: sunset,
+		Link:   "https://example.com/migrate-v2",
+	})
+	app.Version("v1").DELETE("/users/:id", func(c *Context) {})
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("Accept-Version", "v1")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Deprecation"); got != "@1780272000" {
+		t.Errorf("Expected Deprecation '@1780272000', got '%s'", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Sun, 31 Jan 2027 00:00:00 GMT" {
+		t.Errorf("Expected Sunset 'Sun, 31 Jan 2027 00:00:00 GMT', got '%s'", got)
+	}
+	if got := w.Header().Get("Link"); got != `<https://example.com/migrate-v2>; rel="deprecation"` {
+		t.Errorf("Expected deprecation Link, got '%s'", got)
+	}
+	if got := w.Header().Values("Vary"); len(got) != 2 || got[0] != "Accept-Version" || got[1] != "Accept" {
+		t.Errorf("Expected Vary 'Accept-Version, Accept', got %v", got)
+	}
+
+	req = httptest.NewRequest("GET", "/users/1", nil)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Deprecation"); got != "" {
+		t.Errorf("Expected no Deprecation header for v2, got '%s'", got)
+	}
+
+	for _, route := range app.Routes() {
+		if deprecated := route.Version == "v1"; route.Deprecated != deprecated {
+			t.Errorf("%s %s [%s]: expected Deprecated %v", route.Method, route.Path, route.Version, deprecated)
+		}
+	}
+}
+
+func TestAcceptVersion(t *testing.T) {
+	tests := []struct {
+		accept string
+		vendor string
+		want   string
+	}{
+		{"application/vnd.acme.v3+json", "", "v3"},
+		{"application/vnd.acme.v3+json", "ACME", "v3"},
+		{"application/vnd.github.v3.raw+json", "github.v3", "raw"},
+		{"application/vnd.api+json", "", ""},
+		{"application/json", "", ""},
+		{"text/plain;q=0.5, application/json;version=2.1", "", "2.1"},
+	}
+
+	for _, tt := range tests {
+		if got := acceptVersion(tt.accept, tt.vendor); got != tt.want {
+			t.Errorf("acceptVersion(%q, %q): expected %q, got %q", tt.accept, tt.vendor, tt.want, got)
+		}
+	}
+}
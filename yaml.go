@@ -0,0 +1,725 @@
+package gofsen
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Nombres du schéma core de YAML 1.2 : 0755 est décimal, 1_000 et 0b11 sont des chaînes
+var (
+	yamlIntPattern   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlFloatPattern = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// yamlEscapes séquences d'échappement d'une chaîne entre guillemets doubles,
+// hors \x, \u et \U
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v",
+	'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// yamlLine ligne significative d'un document YAML (ni vide ni commentaire)
+type yamlLine struct {
+	number int    // index de la ligne dans le document
+	indent int    // nombre d'espaces en début de ligne
+	text   string // contenu sans indentation ni commentaire
+}
+
+// yamlParser analyse le sous-ensemble de YAML utilisé par les documents OpenAPI :
+// maps et listes en bloc, scalaires simples ou entre guillemets (sur une ou
+// plusieurs lignes), collections en ligne ([a, b], {a: b}), blocs littéraux | et >,
+// ancres (&nom), alias (*nom) et clés de fusion (<<). Les tags sont ignorés,
+// sauf !!str ; les documents multiples ne sont pas supportés.
+type yamlParser struct {
+	raw     []string
+	lines   []yamlLine
+	pos     int
+	anchors map[string]interface{}
+}
+
+// parseYAML convertit un document YAML en valeurs génériques
+// (map[string]interface{}, []interface{}, string, float64, bool, nil)
+func parseYAML(data []byte) (interface{}, error) {
+	p := &yamlParser{raw: strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")}
+	for i, raw := range p.raw {
+		text := strings.TrimRight(stripYAMLComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" || strings.HasPrefix(trimmed, "%") {
+			continue
+		}
+		if trimmed[0] == '\t' {
+			return nil, fmt.Errorf("yaml: line %d: tabs are not allowed for indentation", i+1)
+		}
+		if trimmed == "..." {
+			break
+		}
+		p.lines = append(p.lines, yamlLine{number: i, indent: len(text) - len(trimmed), text: trimmed})
+	}
+
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	value, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected content %q", p.lines[p.pos].text)
+	}
+	return value, nil
+}
+
+// parseBlock analyse la collection qui commence à la ligne courante
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	line := p.lines[p.pos]
+	if isYAMLSequenceItem(line.text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitYAMLKey(line.text); ok {
+		return p.parseMapping(indent)
+	}
+	p.pos++
+	return p.parseInline(indent-1, line.text)
+}
+
+// parseMapping analyse les paires clé: valeur de même indentation
+func (p *yamlParser) parseMapping(indent int) (interface{}, error) {
+	m := make(map[string]interface{})
+	var merges []map[string]interface{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		if isYAMLSequenceItem(line.text) {
+			break
+		}
+
+		rawKey, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, p.errorf("expected 'key: value', got %q", line.text)
+		}
+		name, err := parseYAMLKey(rawKey)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := m[name]; exists {
+			return nil, p.errorf("duplicate key %q", name)
+		}
+		p.pos++
+
+		value, err := p.parseValue(indent, rest)
+		if err != nil {
+			return nil, err
+		}
+		if name == "<<" {
+			// Clé de fusion : <<: *base ou <<: [*a, *b]
+			sources, err := yamlMergeSources(value)
+			if err != nil {
+				return nil, err
+			}
+			merges = append(merges, sources...)
+			continue
+		}
+		m[name] = value
+	}
+
+	// Les clés explicites, puis les premières sources, l'emportent
+	for _, source := range merges {
+		for key, value := range source {
+			if _, exists := m[key]; !exists {
+				m[key] = value
+			}
+		}
+	}
+	return m, nil
+}
+
+// yamlMergeSources retourne les maps désignées par la valeur d'une clé de fusion
+func yamlMergeSources(value interface{}) ([]map[string]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}, nil
+	case []interface{}:
+		sources := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			source, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("yaml: merge key expects mappings, got %T", item)
+			}
+			sources = append(sources, source)
+		}
+		return sources, nil
+	}
+	return nil, fmt.Errorf("yaml: merge key expects a mapping or a list of mappings, got %T", value)
+}
+
+// parseSequence analyse les éléments "- " de même indentation
+func (p *yamlParser) parseSequence(indent int) (interface{}, error) {
+	list := []interface{}{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isYAMLSequenceItem(line.text) {
+			if line.indent > indent {
+				return nil, p.errorf("unexpected indentation")
+			}
+			break
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		if rest == "" {
+			p.pos++
+			value, err := p.parseValue(indent, "")
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+			continue
+		}
+
+		// "- key: value" ouvre une map dont les clés suivantes sont alignées
+		// sur la première : on réécrit la ligne comme si elle était en bloc
+		if _, _, ok := splitYAMLKey(rest); ok || isYAMLSequenceItem(rest) {
+			p.lines[p.pos] = yamlLine{number: line.number, indent: indent + len(line.text) - len(rest), text: rest}
+			value, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+			continue
+		}
+
+		p.pos++
+		value, err := p.parseValue(indent, rest)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+// parseValue analyse la valeur d'une clé ou d'un élément : en ligne (rest),
+// ou en bloc sur les lignes suivantes plus indentées
+func (p *yamlParser) parseValue(indent int, rest string) (interface{}, error) {
+	anchor, _, body := splitYAMLProperties(rest)
+	if body != "" {
+		return p.parseInline(indent, rest)
+	}
+
+	var value interface{}
+	if p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		// Une liste peut être au même niveau que sa clé
+		if next.indent > indent || next.indent == indent && isYAMLSequenceItem(next.text) {
+			var err error
+			if value, err = p.parseBlock(next.indent); err != nil {
+				return nil, err
+			}
+		}
+	}
+	p.setAnchor(anchor, value)
+	return value, nil
+}
+
+// parseInline analyse une valeur qui commence sur la ligne de sa clé et peut
+// se poursuivre sur les lignes suivantes plus indentées que indent
+func (p *yamlParser) parseInline(indent int, text string) (interface{}, error) {
+	anchor, _, body := splitYAMLProperties(text)
+	if body != "" && (body[0] == '|' || body[0] == '>') {
+		value := p.parseBlockScalar(indent, body)
+		p.setAnchor(anchor, value)
+		return value, nil
+	}
+	if body != "" && (body[0] == '"' || body[0] == '\'') && yamlQuoteEnd(body, body[0], 1) < 0 {
+		value, err := p.parseMultilineQuoted(body)
+		if err != nil {
+			return nil, err
+		}
+		p.setAnchor(anchor, value)
+		return value, nil
+	}
+	return p.parseNode(p.continuePlain(indent, text))
+}
+
+// continuePlain ajoute à text les lignes suivantes plus indentées que indent,
+// pour un scalaire simple ou une collection en ligne écrits sur plusieurs lignes.
+// Les retours à la ligne sont repliés en espaces, les lignes vides en sauts de ligne.
+func (p *yamlParser) continuePlain(indent int, text string) string {
+	flow := text[0] == '[' || text[0] == '{'
+	prev := p.lines[p.pos-1].number
+	for p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		line := p.lines[p.pos]
+		if _, _, ok := splitYAMLKey(line.text); ok && !flow {
+			// "clé: valeur" n'est pas permis dans un scalaire simple
+			break
+		}
+
+		separator := " "
+		if blank := countBlankLines(p.raw[prev+1 : line.number]); blank > 0 {
+			separator = strings.Repeat("\n", blank)
+		}
+		text += separator + line.text
+		prev = line.number
+		p.pos++
+	}
+	return text
+}
+
+// parseMultilineQuoted lit un scalaire entre guillemets dont le guillemet
+// fermant se trouve sur une ligne suivante. Les retours à la ligne sont
+// repliés en espaces, les lignes vides en sauts de ligne.
+func (p *yamlParser) parseMultilineQuoted(text string) (interface{}, error) {
+	quote := text[0]
+	newline := "\n"
+	if quote == '"' {
+		// Échappement interprété par strconv.Unquote
+		newline = `\n`
+	}
+
+	folded := strings.TrimRight(text, " \t")
+	blank := false
+	for i := p.lines[p.pos-1].number + 1; i < len(p.raw); i++ {
+		line := strings.TrimSpace(p.raw[i])
+		if line == "" {
+			folded += newline
+			blank = true
+			continue
+		}
+
+		end := yamlQuoteEnd(line, quote, 0)
+		content := line
+		if end >= 0 {
+			content = line[:end+1]
+		}
+		switch {
+		case blank:
+		case quote == '"' && strings.HasSuffix(folded, `\`) && !strings.HasSuffix(folded, `\\`):
+			// Retour à la ligne échappé : les lignes sont jointes sans espace
+			folded = folded[:len(folded)-1]
+		default:
+			folded += " "
+		}
+		folded += content
+		blank = false
+		if end < 0 {
+			continue
+		}
+
+		// Sauter les lignes significatives consommées par le scalaire
+		for p.pos < len(p.lines) && p.lines[p.pos].number <= i {
+			p.pos++
+		}
+		if rest := strings.TrimSpace(stripYAMLComment(line[end+1:])); rest != "" {
+			return nil, fmt.Errorf("yaml: line %d: unexpected %q after quoted string", i+1, rest)
+		}
+		return parseYAMLScalar(folded)
+	}
+	return nil, p.errorf("unterminated quoted string %s", text)
+}
+
+// parseNode analyse une valeur écrite sur une seule ligne : alias, collection
+// en ligne ou scalaire, précédés d'éventuelles propriétés (&ancre, !tag)
+func (p *yamlParser) parseNode(text string) (interface{}, error) {
+	anchor, tag, text := splitYAMLProperties(strings.TrimSpace(text))
+
+	var value interface{}
+	var err error
+	switch {
+	case strings.HasPrefix(text, "*"):
+		var ok bool
+		if value, ok = p.anchors[text[1:]]; !ok {
+			return nil, fmt.Errorf("yaml: unknown alias %s", text)
+		}
+	case text != "" && (text[0] == '[' || text[0] == '{'):
+		var rest string
+		value, rest, err = p.parseFlow(text)
+		if err == nil && strings.TrimSpace(rest) != "" {
+			err = fmt.Errorf("yaml: unexpected %q after collection", rest)
+		}
+	case tag == "!!str" && text != "" && text[0] != '"' && text[0] != '\'':
+		value = text
+	default:
+		value, err = parseYAMLScalar(text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.setAnchor(anchor, value)
+	return value, nil
+}
+
+// setAnchor enregistre la valeur sous le nom de l'ancre, pour les alias suivants
+func (p *yamlParser) setAnchor(anchor string, value interface{}) {
+	if anchor == "" {
+		return
+	}
+	if p.anchors == nil {
+		p.anchors = make(map[string]interface{})
+	}
+	p.anchors[anchor] = value
+}
+
+// parseBlockScalar lit un bloc littéral (|) ou replié (>) à partir des lignes brutes
+func (p *yamlParser) parseBlockScalar(indent int, header string) string {
+	start := p.lines[p.pos-1].number + 1
+	var body []string
+	blockIndent := -1
+	end := start
+	for i := start; i < len(p.raw); i++ {
+		raw := strings.TrimRight(p.raw[i], " \r")
+		trimmed := strings.TrimLeft(raw, " ")
+		if trimmed == "" {
+			body = append(body, "")
+			continue
+		}
+		lineIndent := len(raw) - len(trimmed)
+		if lineIndent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = lineIndent
+		}
+		if lineIndent < blockIndent {
+			break
+		}
+		body = append(body, raw[blockIndent:])
+		end = i + 1
+	}
+	body = body[:min(len(body), end-start)]
+
+	// Sauter les lignes significatives consommées par le bloc
+	for p.pos < len(p.lines) && p.lines[p.pos].number < end {
+		p.pos++
+	}
+
+	var text string
+	if header[0] == '|' {
+		text = strings.Join(body, "\n")
+	} else {
+		var b strings.Builder
+		for i, line := range body {
+			switch {
+			case i == 0:
+			case line == "" || body[i-1] == "":
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(line)
+		}
+		text = b.String()
+	}
+
+	switch {
+	case strings.Contains(header, "-"):
+		return strings.TrimRight(text, "\n")
+	case strings.Contains(header, "+"):
+		return text + "\n"
+	}
+	return strings.TrimRight(text, "\n") + "\n"
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos < len(p.lines) {
+		line = p.lines[p.pos].number + 1
+	}
+	return fmt.Errorf("yaml: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// isYAMLSequenceItem indique si la ligne est un élément de liste
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLProperties sépare l'ancre (&nom) et le tag (!tag) placés en tête d'une valeur
+func splitYAMLProperties(text string) (anchor, tag, rest string) {
+	rest = text
+	for rest != "" && (rest[0] == '&' || rest[0] == '!') {
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		if rest[0] == '&' {
+			anchor = rest[1:end]
+		} else {
+			tag = rest[:end]
+		}
+		rest = strings.TrimLeft(rest[end:], " \t")
+	}
+	return anchor, tag, rest
+}
+
+// yamlQuoteEnd retourne la position du guillemet fermant quote dans s à partir
+// de from, ou -1 s'il n'y en a pas. Les guillemets échappés sont ignorés.
+func yamlQuoteEnd(s string, quote byte, from int) int {
+	for i := from; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// countBlankLines compte les lignes vides parmi lines
+func countBlankLines(lines []string) int {
+	n := 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// splitYAMLKey sépare "clé: valeur" en dehors des guillemets et collections en ligne
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i == len(text)-1 || text[i+1] == ' ' || text[0] == '"' || text[0] == '\''):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripYAMLComment retire un commentaire # (précédé d'un espace ou en début de ligne)
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" [{,:-", rune(line[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' '):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseYAMLKey retourne le nom d'une clé, sans ses éventuels guillemets
+func parseYAMLKey(key string) (string, error) {
+	if key != "" && (key[0] == '"' || key[0] == '\'') {
+		value, err := parseYAMLScalar(key)
+		if err != nil {
+			return "", err
+		}
+		return value.(string), nil
+	}
+	return key, nil
+}
+
+// parseYAMLScalar analyse un scalaire simple ou entre guillemets
+func parseYAMLScalar(text string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	switch text[0] {
+	case '"':
+		return unquoteYAML(text)
+	case '\'':
+		if len(text) < 2 || text[len(text)-1] != '\'' {
+			return nil, fmt.Errorf("yaml: invalid quoted string %s", text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+
+	switch text {
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	return parseYAMLNumber(text), nil
+}
+
+// parseYAMLNumber convertit un nombre du schéma core de YAML 1.2 (décimal,
+// 0o octal, 0x hexadécimal, flottant, .inf, .nan), ou retourne text tel quel
+func parseYAMLNumber(text string) interface{} {
+	switch {
+	case yamlIntPattern.MatchString(text) || yamlFloatPattern.MatchString(text):
+		if n, err := strconv.ParseFloat(text, 64); err == nil {
+			return n
+		}
+	case strings.HasPrefix(text, "0o"):
+		if n, err := strconv.ParseUint(text[2:], 8, 64); err == nil {
+			return float64(n)
+		}
+	case strings.HasPrefix(text, "0x"):
+		if n, err := strconv.ParseUint(text[2:], 16, 64); err == nil {
+			return float64(n)
+		}
+	}
+
+	switch strings.TrimLeft(text, "+") {
+	case ".inf", ".Inf", ".INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	}
+	switch text {
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+	return text
+}
+
+// unquoteYAML décode une chaîne entre guillemets doubles et ses échappements YAML
+func unquoteYAML(text string) (string, error) {
+	if len(text) < 2 || yamlQuoteEnd(text, '"', 1) != len(text)-1 {
+		return "", fmt.Errorf("yaml: invalid quoted string %s", text)
+	}
+
+	body := text[1 : len(text)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] != '\\' {
+			b.WriteByte(body[i])
+			continue
+		}
+		i++
+		if i == len(body) {
+			return "", fmt.Errorf("yaml: invalid quoted string %s", text)
+		}
+		if escaped, ok := yamlEscapes[body[i]]; ok {
+			b.WriteString(escaped)
+			continue
+		}
+
+		size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[body[i]]
+		if size == 0 || i+size >= len(body) {
+			return "", fmt.Errorf("yaml: invalid escape \\%c in %s", body[i], text)
+		}
+		code, err := strconv.ParseUint(body[i+1:i+1+size], 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("yaml: invalid escape \\%s in %s", body[i:i+1+size], text)
+		}
+		b.WriteRune(rune(code))
+		i += size
+	}
+	return b.String(), nil
+}
+
+// parseFlow analyse une collection en ligne et retourne le texte restant
+func (p *yamlParser) parseFlow(text string) (interface{}, string, error) {
+	open := text[0]
+	closing := byte(']')
+	if open == '{' {
+		closing = '}'
+	}
+	text = strings.TrimLeft(text[1:], " ")
+
+	var list []interface{}
+	m := make(map[string]interface{})
+	for {
+		if text == "" {
+			return nil, "", fmt.Errorf("yaml: unterminated collection")
+		}
+		if text[0] == closing {
+			if open == '{' {
+				return m, text[1:], nil
+			}
+			if list == nil {
+				list = []interface{}{}
+			}
+			return list, text[1:], nil
+		}
+
+		item, rest, err := parseYAMLFlowItem(text)
+		if err != nil {
+			return nil, "", err
+		}
+		if open == '{' {
+			key, value, ok := splitYAMLKey(item)
+			if !ok {
+				return nil, "", fmt.Errorf("yaml: expected 'key: value' in %q", item)
+			}
+			k, err := parseYAMLKey(key)
+			if err != nil {
+				return nil, "", err
+			}
+			v, err := p.parseNode(value)
+			if err != nil {
+				return nil, "", err
+			}
+			m[k] = v
+		} else {
+			v, err := p.parseNode(item)
+			if err != nil {
+				return nil, "", err
+			}
+			list = append(list, v)
+		}
+
+		text = strings.TrimLeft(rest, " ")
+		if strings.HasPrefix(text, ",") {
+			text = strings.TrimLeft(text[1:], " ")
+		}
+	}
+}
+
+// parseYAMLFlowItem extrait le texte brut d'un élément d'une collection en
+// ligne, jusqu'au séparateur de même niveau
+func parseYAMLFlowItem(text string) (string, string, error) {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case (c == ']' || c == '}') && depth > 0:
+			depth--
+		case depth == 0 && (c == ',' || c == ']' || c == '}'):
+			return strings.TrimSpace(text[:i]), text[i:], nil
+		}
+	}
+	return "", "", fmt.Errorf("yaml: unterminated collection")
+}
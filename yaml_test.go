@@ -0,0 +1,305 @@
+package gofsen
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	data := []byte(`# Contrat de l'équipe billing
+openapi: 3.0.3
+info:
+  title: 'Billing: v1'
+  version: "1.0"
+  description: |
+    Multi-line
+    description
+tags: [invoices, "a, b"]
+paths:
+  /invoices/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+      schema: {type: integer, minimum: 1}
+    get:
+      summary: Get an invoice   # commentaire
+      responses:
+        "200":
+          description: >
+            Folded
+            text
+        404: {description: Not found}
+empty:
+nested:
+  - - 1
+    - 2.5
+  - key: value
+    other: ~
+`)
+
+	value, err := parseYAML(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Billing: v1",
+			"version":     "1.0",
+			"description": "Multi-line\ndescription\n",
+		},
+		"tags": []interface{}{"invoices", "a, b"},
+		"paths": map[string]interface{}{
+			"/invoices/{id}": map[string]interface{}{
+				"parameters": []interface{}{
+					map[string]interface{}{
+						"name":     "id",
+						"in":       "path",
+						"required": true,
+						"schema":   map[string]interface{}{"type": "integer", "minimum": float64(1)},
+					},
+				},
+				"get": map[string]interface{}{
+					"summary": "Get an invoice",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{"description": "Folded text\n"},
+						"404": map[string]interface{}{"description": "Not found"},
+					},
+				},
+			},
+		},
+		"empty": nil,
+		"nested": []interface{}{
+			[]interface{}{float64(1), 2.5},
+			map[string]interface{}{"key": "value", "other": nil},
+		},
+	}
+
+	if !reflect.DeepEqual(value, want) {
+		got, _ := json.MarshalIndent(value, "", "  ")
+		t.Errorf("Unexpected result:\n%s", got)
+	}
+}
+
+func TestParseYAMLScalars(t *testing.T) {
+	tests := []struct {
+		text string
+		want interface{}
+	}{
+		{"12", float64(12)},
+		{"-7", float64(-7)},
+		{"0755", float64(755)},
+		{"012", float64(12)},
+		{"0o755", float64(493)},
+		{"0x1F", float64(31)},
+		{"1.5e3", 1500.0},
+		{".5", 0.5},
+		{"1_000", "1_000"},
+		{"0b11", "0b11"},
+		{"1e", "1e"},
+		{"Infinity", "Infinity"},
+		{"NaN", "NaN"},
+		{"+0x1F", "+0x1F"},
+		{`"a\/b"`, "a/b"},
+		{`"\e[0m"`, "\x1b[0m"},
+		{`"nul\0"`, "nul\x00"},
+		{`"\N\_\L\P"`, "\u0085\u00a0\u2028\u2029"},
+		{`"\x41\u00e9\U0001F600 \"q\" \\"`, "Aé😀 \"q\" \\"},
+		{`"tab\	end"`, "tab\tend"},
+	}
+
+	for _, tt := range tests {
+		value, err := parseYAML([]byte("v: " + tt.text + "\n"))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.text, err)
+			continue
+		}
+		if got := value.(map[string]interface{})["v"]; got != tt.want {
+			t.Errorf("%s: expected %#v, got %#v", tt.text, tt.want, got)
+		}
+	}
+
+	for _, text := range []string{`"\q"`, `"\x4"`, `"\uZZZZ"`, `"a"b"`} {
+		if _, err := parseYAML([]byte("v: " + text + "\n")); err == nil {
+			t.Errorf("%s: expected error", text)
+		}
+	}
+}
+
+func TestParseYAMLRoundTrip(t *testing.T) {
+	app := New()
+	app.GET("/users/:id<int>", func(c *Context) {}).
+		Named("getUser").
+		Describe("Get a user").
+		Tag("users").
+		Annotate("auth", "bearer").
+		Returns(200, apiUser{}).
+		Returns(404, nil)
+	app.POST("/users", func(c *Context) {}).
+		Accepts(apiUser{}).
+		Returns(201, &apiUser{})
+	app.GET("/files/*filepath", func(c *Context) {}).Deprecate()
+
+	doc := app.OpenAPI(OpenAPIConfig{Title: "Users", Description: "Line 1\nLine \"2\"", Servers: []string{"https://api.example.com"}})
+
+	data, err := doc.YAML()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, err := parseYAML(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var want interface{}
+	raw, _ := doc.JSON()
+	json.Unmarshal(raw, &want)
+
+	if !reflect.DeepEqual(parsed, want) {
+		t.Errorf("Expected YAML to parse back to the document, got %v", parsed)
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	for _, data := range []string{
+		"a: 1\n  b: 2\n",
+		"a: 1\na: 2\n",
+		"a: [1, 2\n",
+		"a: *ref\n",
+		"a: &ref 1\nb: *other\n",
+		"a: \"open\nb: 2\n",
+		"<<: 1\n",
+		"a:\n\t- 1\n",
+	} {
+		if _, err := parseYAML([]byte(data)); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
+
+// petstoreSpec extrait d'un contrat tiers, dans le style des documents écrits
+// à la main : descriptions repliées sur plusieurs lignes, ancres et alias
+const petstoreSpec = `openapi: 3.0.3
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  description: This is a sample Pet Store Server based on the OpenAPI 3.0
+    specification.  You can find out more about
+    Swagger at [https://swagger.io](https://swagger.io).
+
+    Some useful links are listed below.
+  termsOfService: http://swagger.io/terms/
+  version: 1.0.11
+x-defaults:
+  pageSize: &pageSize
+    name: limit
+    in: query
+    description: "How many items to return at one time
+      (max 100)"
+    schema: {type: integer, maximum: 100}
+  error: &error
+    description: unexpected error
+    content:
+      application/json:
+        schema:
+          $ref: '#/components/schemas/Error'
+paths:
+  /pet/findByStatus:
+    get:
+      tags: [pet]
+      summary: Finds Pets by status
+      description: Multiple status values can be provided with comma separated
+        strings
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+            default: available
+            enum: [available, pending,
+              sold]
+        - *pageSize
+      responses:
+        '200':
+          description: successful operation
+        '400':
+          <<: *error
+          description: Invalid status value
+        default: *error
+components:
+  schemas:
+    Error:
+      type: object
+      required: [code, message]
+      properties:
+        code: {type: integer, format: int32}
+        message:
+          type: string
+          example: !!str 404
+`
+
+func TestParseYAMLThirdPartySpec(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(petstoreSpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	info := doc["info"].(map[string]interface{})
+	wantDescription := "This is a sample Pet Store Server based on the OpenAPI 3.0 specification.  " +
+		"You can find out more about Swagger at [https://swagger.io](https://swagger.io).\nSome useful links are listed below."
+	if info["description"] != wantDescription {
+		t.Errorf("Expected folded description %q, got %q", wantDescription, info["description"])
+	}
+	if info["version"] != "1.0.11" {
+		t.Errorf("Expected version '1.0.11', got %v", info["version"])
+	}
+
+	op := doc["paths"].(map[string]interface{})["/pet/findByStatus"].(map[string]interface{})["get"].(map[string]interface{})
+	if op["description"] != "Multiple status values can be provided with comma separated strings" {
+		t.Errorf("Unexpected operation description %q", op["description"])
+	}
+
+	params := op["parameters"].([]interface{})
+	status := params[0].(map[string]interface{})["schema"].(map[string]interface{})
+	if !reflect.DeepEqual(status["enum"], []interface{}{"available", "pending", "sold"}) {
+		t.Errorf("Expected multi-line enum, got %v", status["enum"])
+	}
+	limit := params[1].(map[string]interface{})
+	if limit["name"] != "limit" || limit["description"] != "How many items to return at one time (max 100)" {
+		t.Errorf("Expected aliased limit parameter, got %v", limit)
+	}
+
+	responses := op["responses"].(map[string]interface{})
+	invalid := responses["400"].(map[string]interface{})
+	if invalid["description"] != "Invalid status value" || invalid["content"] == nil {
+		t.Errorf("Expected merged 400 response, got %v", invalid)
+	}
+	if fallback := responses["default"].(map[string]interface{}); fallback["description"] != "unexpected error" {
+		t.Errorf("Expected aliased default response, got %v", fallback)
+	}
+
+	message := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Error"].(map[string]interface{})["properties"].(map[string]interface{})["message"].(map[string]interface{})
+	if message["example"] != "404" {
+		t.Errorf("Expected !!str example '404', got %v", message["example"])
+	}
+
+	// Le document analysé sert directement à la validation
+	app := New()
+	app.Use(ValidateOpenAPI(doc))
+	app.GET("/pet/findByStatus", func(c *Context) { c.Text("ok") })
+
+	for target, code := range map[string]int{
+		"/pet/findByStatus?status=sold&limit=10": 200,
+		"/pet/findByStatus?status=lost":          400,
+		"/pet/findByStatus?limit=500":            400,
+	} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		if w.Code != code {
+			t.Errorf("%s: expected status %d, got %d", target, code, w.Code)
+		}
+	}
+}